@@ -0,0 +1,252 @@
+package termtools
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// StripANSI removes ANSI escape sequences from s.
+func StripANSI(s string) string {
+	var b strings.Builder
+	for _, tok := range tokenize(s) {
+		if !tok.esc {
+			b.WriteString(tok.s)
+		}
+	}
+	return b.String()
+}
+
+// VisibleWidth returns number of terminal columns occupied by s when printed.
+// ANSI escape sequences are ignored, East Asian wide characters and most emoji count
+// as two columns, combining marks and other zero width characters are not counted.
+// Grapheme clusters (like emoji joined with zero width joiner or flags) are counted as
+// a single character. s is expected to hold single line of text.
+func VisibleWidth(s string) (width int) {
+	for _, tok := range tokenize(s) {
+		width += tok.width
+	}
+	return
+}
+
+// Truncate cuts s so that it occupies at most width columns. If s is cut, tail (for
+// example "…") is appended and the result including tail fits in width.
+// Escape sequences are preserved and if s is cut while some style is active
+// Reset is added so that style does not leak to subsequent output. Negative width is
+// treated as zero.
+func Truncate(s string, width int, tail string) string {
+	if width < 0 {
+		width = 0
+	}
+	if VisibleWidth(s) <= width {
+		return s
+	}
+	tailWidth := VisibleWidth(tail)
+	if tailWidth > width {
+		tail, tailWidth = "", 0
+	}
+	return slice(s, 0, width-tailWidth, tail)
+}
+
+// Slice returns part of s occupying visible columns from start (inclusive) to
+// end (exclusive). Columns are counted from zero. If end is negative s is sliced till the end.
+// Escape sequences preceding start are preserved, so that slice starts with the same style as
+// the original string at this column. Reset is appended if some style is active at end of slice.
+// Wide characters which do not fit fully in the requested range are dropped.
+func Slice(s string, start, end int) string {
+	return slice(s, start, end, "")
+}
+
+func slice(s string, start, end int, tail string) string {
+	var (
+		b      strings.Builder
+		col    int
+		styled bool
+	)
+	for _, tok := range tokenize(s) {
+		if tok.esc {
+			if end >= 0 && col >= end {
+				continue
+			}
+			b.WriteString(tok.s)
+			if isSGR(tok.s) {
+				styled = !isReset(tok.s)
+			}
+			continue
+		}
+		if col >= start && (end < 0 || col+tok.width <= end) {
+			b.WriteString(tok.s)
+		}
+		col += tok.width
+	}
+	b.WriteString(tail)
+	if styled {
+		b.WriteString(Reset)
+	}
+	return b.String()
+}
+
+// isSGR reports whether escape sequence sets graphic rendition (color and style).
+func isSGR(seq string) bool {
+	return strings.HasPrefix(seq, Esc+"[") && strings.HasSuffix(seq, "m")
+}
+
+func isReset(seq string) bool {
+	return seq == Reset || seq == Esc+"[m"
+}
+
+// token is either an escape sequence or a grapheme cluster.
+type token struct {
+	s     string
+	esc   bool
+	width int
+}
+
+func tokenize(s string) (tokens []token) {
+	for len(s) > 0 {
+		var n int
+		if s[0] == '\x1b' {
+			n = escapeLength(s)
+			tokens = append(tokens, token{s: s[:n], esc: true})
+		} else {
+			var width int
+			n, width = graphemeLength(s)
+			tokens = append(tokens, token{s: s[:n], width: width})
+		}
+		s = s[n:]
+	}
+	return
+}
+
+// escapeLength returns length in bytes of escape sequence at the beginning of s.
+func escapeLength(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	switch s[1] {
+	case '[': // CSI: parameters and intermediates followed by final byte
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']', 'P', '_', '^': // OSC, DCS, APC, PM: terminated by BEL or ST
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	}
+	return 2
+}
+
+// graphemes splits s (which must not contain escape sequences) into grapheme clusters.
+func graphemes(s string) (clusters []string) {
+	for len(s) > 0 {
+		n, _ := graphemeLength(s)
+		clusters = append(clusters, s[:n])
+		s = s[n:]
+	}
+	return
+}
+
+// graphemeLength returns length in bytes and width in columns of the grapheme
+// cluster at the beginning of s. This is a simplified version of Unicode segmentation
+// rules which handles combining marks, emoji modifiers and sequences,
+// and regional indicator pairs.
+func graphemeLength(s string) (n, width int) {
+	r, size := utf8.DecodeRuneInString(s)
+	n, width = size, RuneWidth(r)
+	if r == '\r' && len(s) > 1 && s[1] == '\n' {
+		return 2, 0
+	}
+	regional := isRegionalIndicator(r)
+	joined := false
+	for n < len(s) {
+		next, size := utf8.DecodeRuneInString(s[n:])
+		switch {
+		case joined:
+			joined = false
+		case next == zeroWidthJoiner:
+			joined = true
+		case next == emojiPresentation:
+			width = 2
+		case regional && isRegionalIndicator(next):
+			regional = false
+			width = 2
+		case isExtending(next):
+		default:
+			return
+		}
+		n += size
+	}
+	return
+}
+
+const (
+	zeroWidthJoiner   = '\u200d'
+	emojiPresentation = '\ufe0f' // variation selector 16
+)
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// isExtending reports whether r extends preceding grapheme cluster.
+func isExtending(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		(r >= 0x1f3fb && r <= 0x1f3ff) || // emoji skin tone modifiers
+		(r >= 0xe0020 && r <= 0xe007f) || // tags
+		r == '\u200c' // zero width non-joiner
+}
+
+// RuneWidth returns number of columns occupied by r in terminal: 0 for control
+// characters and zero width characters, 2 for East Asian wide and fullwidth
+// characters and emoji, and 1 otherwise.
+func RuneWidth(r rune) int {
+	switch {
+	case r < 0x20 || (r >= 0x7f && r < 0xa0):
+		return 0
+	case r < 0x300:
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf), r >= 0x1160 && r <= 0x11ff:
+		return 0
+	}
+	for _, rng := range wideRanges {
+		if r < rng[0] {
+			break
+		}
+		if r <= rng[1] {
+			return 2
+		}
+	}
+	return 1
+}
+
+// wideRanges lists (sorted) ranges of East Asian wide and fullwidth characters
+// and emoji with default emoji presentation.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115f}, {0x231a, 0x231b}, {0x2329, 0x232a}, {0x23e9, 0x23ec}, {0x23f0, 0x23f0},
+	{0x23f3, 0x23f3}, {0x25fd, 0x25fe}, {0x2614, 0x2615}, {0x2648, 0x2653}, {0x267f, 0x267f},
+	{0x2693, 0x2693}, {0x26a1, 0x26a1}, {0x26aa, 0x26ab}, {0x26bd, 0x26be}, {0x26c4, 0x26c5},
+	{0x26ce, 0x26ce}, {0x26d4, 0x26d4}, {0x26ea, 0x26ea}, {0x26f2, 0x26f3}, {0x26f5, 0x26f5},
+	{0x26fa, 0x26fa}, {0x26fd, 0x26fd}, {0x2705, 0x2705}, {0x270a, 0x270b}, {0x2728, 0x2728},
+	{0x274c, 0x274c}, {0x274e, 0x274e}, {0x2753, 0x2755}, {0x2757, 0x2757}, {0x2795, 0x2797},
+	{0x27b0, 0x27b0}, {0x27bf, 0x27bf}, {0x2b1b, 0x2b1c}, {0x2b50, 0x2b50}, {0x2b55, 0x2b55},
+	{0x2e80, 0x303e}, {0x3041, 0x33ff}, {0x3400, 0x4dbf}, {0x4e00, 0x9fff}, {0xa000, 0xa4cf},
+	{0xa960, 0xa97f}, {0xac00, 0xd7a3}, {0xf900, 0xfaff}, {0xfe10, 0xfe19}, {0xfe30, 0xfe6f},
+	{0xff00, 0xff60}, {0xffe0, 0xffe6}, {0x16fe0, 0x16fe4}, {0x17000, 0x18cff}, {0x1b000, 0x1b2ff},
+	{0x1f004, 0x1f004}, {0x1f0cf, 0x1f0cf}, {0x1f18e, 0x1f18e}, {0x1f191, 0x1f19a}, {0x1f200, 0x1f251},
+	{0x1f300, 0x1f320}, {0x1f32d, 0x1f335}, {0x1f337, 0x1f37c}, {0x1f37e, 0x1f393}, {0x1f3a0, 0x1f3ca},
+	{0x1f3cf, 0x1f3d3}, {0x1f3e0, 0x1f3f0}, {0x1f3f4, 0x1f3f4}, {0x1f3f8, 0x1f43e}, {0x1f440, 0x1f440},
+	{0x1f442, 0x1f4fc}, {0x1f4ff, 0x1f53d}, {0x1f54b, 0x1f54e}, {0x1f550, 0x1f567}, {0x1f57a, 0x1f57a},
+	{0x1f595, 0x1f596}, {0x1f5a4, 0x1f5a4}, {0x1f5fb, 0x1f64f}, {0x1f680, 0x1f6c5}, {0x1f6cc, 0x1f6cc},
+	{0x1f6d0, 0x1f6d2}, {0x1f6d5, 0x1f6d7}, {0x1f6eb, 0x1f6ec}, {0x1f6f4, 0x1f6fc}, {0x1f7e0, 0x1f7eb},
+	{0x1f90c, 0x1f93a}, {0x1f93c, 0x1f945}, {0x1f947, 0x1f9ff}, {0x1fa70, 0x1faff}, {0x20000, 0x2fffd},
+	{0x30000, 0x3fffd},
+}
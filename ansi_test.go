@@ -0,0 +1,52 @@
+package termtools
+
+import "testing"
+
+func Test_VisibleWidth(t *testing.T) {
+	cases := []struct {
+		s     string
+		width int
+	}{
+		{"hello", 5},
+		{Red + "hello" + Reset, 5},
+		{Esc + "]8;;http://example.com" + Esc + "\\link" + Esc + "]8;;" + Esc + "\\", 4},
+		{"日本語", 6},
+		{"é", 1},
+		{"👍🏽", 2},
+		{"👨‍👩‍👧", 2},
+		{"🇩🇪", 2},
+		{"❤️", 2},
+		{"ｆｕｌｌ", 8},
+	}
+	for _, c := range cases {
+		if w := VisibleWidth(c.s); w != c.width {
+			t.Errorf("VisibleWidth(%q) = %d, want %d", c.s, w, c.width)
+		}
+	}
+	if s := StripANSI(Red + Bold + "x" + Reset); s != "x" {
+		t.Errorf("StripANSI: got %q", s)
+	}
+}
+
+func Test_TruncateSlice(t *testing.T) {
+	cases := []struct {
+		got, want string
+	}{
+		{Truncate("hello world", 20, "…"), "hello world"},
+		{Truncate("hello world", 8, "…"), "hello w…"},
+		{Truncate(Red+"hello"+Reset+" world", 4, "…"), Red + "hel…" + Reset},
+		{Truncate(Red+"hello"+Reset+" world", 7, ""), Red + "hello" + Reset + " w"},
+		{Truncate("日本語", 3, ""), "日"},
+		{Truncate("hello", -1, ""), ""},
+		{Truncate("hello", -1, "…"), ""},
+		{Truncate("hello", 0, ""), ""},
+		{Slice(Red+"hello"+Reset, 1, 3), Red + "el" + Reset},
+		{Slice(Red+"he"+Blue+"llo"+Reset, 3, -1), Red + Blue + "lo" + Reset},
+		{Slice("日本語", 1, 4), "本"},
+	}
+	for i, c := range cases {
+		if c.got != c.want {
+			t.Errorf("case %d: got %q want %q", i, c.got, c.want)
+		}
+	}
+}
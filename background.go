@@ -0,0 +1,226 @@
+package termtools
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// ErrUnknownBackground is returned when background color of terminal can not be queried.
+var ErrUnknownBackground = errors.New("error: could not find out terminal background color")
+
+// backgroundQueryTimeout limits time to wait for terminal reply in DetectBackground.
+const backgroundQueryTimeout = 100 * time.Millisecond
+
+const (
+	backgroundDetect int32 = iota
+	backgroundDark
+	backgroundLight
+)
+
+var (
+	background       int32
+	detectOnce       sync.Once
+	detectedDarkness bool
+)
+
+// SetDarkBackground tells package whether terminal has dark background. It affects which
+// variant of AdaptiveColor is output. See also DetectBackground.
+func SetDarkBackground(dark bool) {
+	if dark {
+		atomic.StoreInt32(&background, backgroundDark)
+		return
+	}
+	atomic.StoreInt32(&background, backgroundLight)
+}
+
+// HasDarkBackground reports whether terminal has dark background. Unless set with SetDarkBackground
+// or DetectBackground, COLORFGBG environment variable is used and if it is not set background
+// is considered dark. HasDarkBackground never queries terminal.
+func HasDarkBackground() bool {
+	switch atomic.LoadInt32(&background) {
+	case backgroundDark:
+		return true
+	case backgroundLight:
+		return false
+	}
+	detectOnce.Do(func() {
+		detectedDarkness = environmentBackground()
+	})
+	return detectedDarkness
+}
+
+// DetectBackground finds out whether terminal has dark background and sets the result with
+// SetDarkBackground. If standard output is a terminal its background color is queried with
+// OSC 11 escape sequence (see QueryBackgroundColor). If terminal does not reply COLORFGBG
+// environment variable is used. If background is still unknown it is considered dark.
+//
+// Querying terminal takes up to 100 milliseconds and reads from controlling terminal, so
+// DetectBackground is never called implicitly. Call it once at startup before reading input.
+func DetectBackground() (dark bool) {
+	dark = environmentBackground()
+	if isTerminal(int(os.Stdout.Fd())) {
+		if c, err := QueryBackgroundColor(backgroundQueryTimeout); err == nil {
+			dark = isDark(c)
+		}
+	}
+	SetDarkBackground(dark)
+	return dark
+}
+
+// environmentBackground reports whether background is dark according to COLORFGBG
+// environment variable, background is considered dark if the variable is not set.
+func environmentBackground() bool {
+	if dark, ok := parseColorFGBG(os.Getenv("COLORFGBG")); ok {
+		return dark
+	}
+	return true
+}
+
+// isDark reports whether c is perceived as a dark color, that is white text
+// is more readable on it than black.
+func isDark(c RGB) bool {
+	return ReadableForeground(c) == white
+}
+
+// parseColorFGBG parses value of COLORFGBG environment variable in form "fg;bg" or "fg;default;bg".
+// Background is considered light if it is white (7) or one of bright colors except for bright black (8).
+func parseColorFGBG(s string) (dark bool, ok bool) {
+	fields := strings.Split(s, ";")
+	bg, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil || bg < 0 || bg > 15 {
+		return false, false
+	}
+	return bg != 7 && bg < 9, true
+}
+
+// QueryBackgroundColor asks terminal for its background color with OSC 11 escape sequence.
+// The query is written to controlling terminal (/dev/tty) which is switched to cbreak mode
+// while waiting for reply. If terminal does not reply within timeout ErrUnknownBackground is returned.
+//
+// Query is followed by request of device attributes which virtually all terminals answer.
+// This allows to return early if terminal does not support OSC 11.
+func QueryBackgroundColor(timeout time.Duration) (RGB, error) {
+	reply, ok := queryTerminal(Esc+"]11;?"+Esc+"\\", timeout)
+	if !ok {
+		return RGB{}, ErrUnknownBackground
+	}
+	return parseOSCColor(reply)
+}
+
+// queryTerminal writes query followed by device attributes request to controlling terminal
+// and returns everything terminal replies up to device attributes. Terminal is switched to
+// cbreak mode while waiting for reply. If terminal is not available or does not reply at all
+// within timeout ok is false.
+func queryTerminal(query string, timeout time.Duration) (reply string, ok bool) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		return "", false
+	}
+	defer tty.Close()
+	fd := int(tty.Fd())
+	state, err := MakeCbreak(fd)
+	if err != nil {
+		return "", false
+	}
+	defer state.Restore()
+
+	if _, err := tty.WriteString(query + Esc + "[c"); err != nil {
+		return "", false
+	}
+	return readTerminalReply(fd, timeout)
+}
+
+// readTerminalReply reads from fd until reply to device attributes request is received
+// or timeout expires. Some terminals do not answer device attributes request, so whatever
+// was read before timeout is returned as well, ok is false only if nothing was read.
+func readTerminalReply(fd int, timeout time.Duration) (reply string, ok bool) {
+	var b []byte
+	buf := make([]byte, 256)
+	deadline := time.Now().Add(timeout)
+	for !hasDeviceAttributes(b) {
+		left := time.Until(deadline)
+		if left <= 0 {
+			break
+		}
+		fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+		n, err := unix.Poll(fds, int(left/time.Millisecond)+1)
+		if err == unix.EINTR {
+			continue
+		}
+		if err != nil || n == 0 {
+			break
+		}
+		n, err = unix.Read(fd, buf)
+		if err != nil || n == 0 {
+			break
+		}
+		b = append(b, buf[:n]...)
+	}
+	return string(b), len(b) > 0
+}
+
+// hasDeviceAttributes reports whether b holds reply to device attributes request ("\x1b[?...c").
+func hasDeviceAttributes(b []byte) bool {
+	i := bytes.Index(b, []byte(Esc+"[?"))
+	if i < 0 {
+		return false
+	}
+	for _, c := range b[i+3:] {
+		switch {
+		case c == 'c':
+			return true
+		case c != ';' && (c < '0' || c > '9'):
+			return false
+		}
+	}
+	return false
+}
+
+// parseOSCColor extracts color from terminal reply to OSC 11 query. The reply looks like
+// "\x1b]11;rgb:rrrr/gggg/bbbb" terminated with BEL or ST, where each component has
+// from 1 to 4 hex digits.
+func parseOSCColor(reply string) (RGB, error) {
+	i := strings.Index(reply, Esc+"]11;")
+	if i < 0 {
+		return RGB{}, ErrUnknownBackground
+	}
+	value := reply[i+len(Esc+"]11;"):]
+	if end := strings.IndexAny(value, "\a"+Esc); end >= 0 {
+		value = value[:end]
+	} else {
+		return RGB{}, ErrUnknownBackground
+	}
+	switch {
+	case strings.HasPrefix(value, "rgb:"):
+		value = value[len("rgb:"):]
+	case strings.HasPrefix(value, "rgba:"):
+		value = value[len("rgba:"):]
+	default:
+		return RGB{}, ErrUnknownBackground
+	}
+	parts := strings.Split(value, "/")
+	if len(parts) < 3 {
+		return RGB{}, ErrUnknownBackground
+	}
+	var rgb [3]uint8
+	for i, part := range parts[:3] {
+		if len(part) < 1 || len(part) > 4 {
+			return RGB{}, ErrUnknownBackground
+		}
+		v, err := strconv.ParseUint(part, 16, 16)
+		if err != nil {
+			return RGB{}, ErrUnknownBackground
+		}
+		max := uint64(1)<<(4*uint(len(part))) - 1
+		rgb[i] = uint8((v*255 + max/2) / max)
+	}
+	return RGB{rgb[0], rgb[1], rgb[2]}, nil
+}
@@ -0,0 +1,172 @@
+package termtools
+
+import (
+	"hash/fnv"
+	"math"
+	"sync"
+)
+
+// DefaultMinContrast is minimal contrast ratio between color and background used by ColorFor
+// and DistinctColors if ColorOptions do not set it.
+const DefaultMinContrast = 3
+
+// defaultCategoricalColors is the number of colors ColorFor picks from if ColorOptions hold no colors.
+const defaultCategoricalColors = 24
+
+// ColorOptions configure choice of colors by ColorFor and DistinctColors.
+type ColorOptions struct {
+	// Colors are candidates to choose from. If empty, colors of 6x6x6 cube of 256 colors palette
+	// are used, so that chosen colors are displayed accurately by terminals supporting 256 colors.
+	Colors []RGB
+	// Background is the color of terminal background. It accepts the same values as Printer
+	// SetColor method. If nil or invalid, black or white is assumed depending on HasDarkBackground.
+	Background interface{}
+	// MinContrast is minimal contrast ratio (see ContrastRatio) between chosen colors and background.
+	// If zero, DefaultMinContrast is used. Negative value disables the check.
+	MinContrast float64
+}
+
+// background returns assumed background color.
+func (opts ColorOptions) background() RGB {
+	if opts.Background != nil {
+		if c, err := parseColor(opts.Background); err == nil {
+			if rgb, ok := c.rgbValue(TrueColor); ok {
+				return rgb
+			}
+		}
+	}
+	if HasDarkBackground() {
+		return black
+	}
+	return white
+}
+
+// readable returns candidate colors having enough contrast with background bg.
+// If there are no such colors all candidates are returned.
+func (opts ColorOptions) readable(bg RGB) []RGB {
+	candidates := opts.Colors
+	if len(candidates) == 0 {
+		candidates = xtermColors[CubeStart:GrayscaleStart]
+	}
+	min := opts.MinContrast
+	if min == 0 {
+		min = DefaultMinContrast
+	}
+	var readable []RGB
+	for _, c := range candidates {
+		if ContrastRatio(c, bg) >= min {
+			readable = append(readable, c)
+		}
+	}
+	if len(readable) == 0 {
+		return candidates
+	}
+	return readable
+}
+
+// categoricalKey identifies cached default set of colors for ColorFor.
+type categoricalKey struct {
+	bg          RGB
+	minContrast float64
+}
+
+var categoricalColors sync.Map
+
+// ColorFor returns color for key. The same key always gets the same color (given the same options),
+// so that it may be used to tell apart output of workers, hosts and alike across runs of program.
+// Key is hashed to one of opts.Colors which are readable on background. If opts hold no colors,
+// the choice is made from 24 perceptually distinct colors (see DistinctColors).
+func ColorFor(key string, opts ColorOptions) RGB {
+	bg := opts.background()
+	var colors []RGB
+	if len(opts.Colors) > 0 {
+		colors = opts.readable(bg)
+	} else {
+		k := categoricalKey{bg, opts.MinContrast}
+		if cached, ok := categoricalColors.Load(k); ok {
+			colors = cached.([]RGB)
+		} else {
+			opts.Background = bg
+			colors = DistinctColors(defaultCategoricalColors, opts)
+			categoricalColors.Store(k, colors)
+		}
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return colors[h.Sum32()%uint32(len(colors))]
+}
+
+// PrinterFor returns Printer with text color returned by ColorFor.
+func PrinterFor(key string, opts ColorOptions) *Printer {
+	return &Printer{style: Style{}.Foreground(ColorFor(key, opts))}
+}
+
+// DistinctColors returns n colors which are as different from each other and from background
+// as possible. Colors are picked from opts.Colors readable on background one by one, every time
+// choosing the color farthest from all colors picked before (distance is measured in CIELAB color space).
+// Result is deterministic. If there are less than n candidates, all of them are returned.
+func DistinctColors(n int, opts ColorOptions) []RGB {
+	bg := opts.background()
+	candidates := opts.readable(bg)
+	labs := make([]lab, len(candidates))
+	// distance from every candidate to the nearest picked color
+	nearest := make([]float64, len(candidates))
+	bgLab := bg.lab()
+	for i, c := range candidates {
+		labs[i] = c.lab()
+		nearest[i] = labs[i].distance(bgLab)
+	}
+	var colors []RGB
+	for len(colors) < n && len(colors) < len(candidates) {
+		best := -1
+		for i := range candidates {
+			if nearest[i] >= 0 && (best < 0 || nearest[i] > nearest[best]) {
+				best = i
+			}
+		}
+		colors = append(colors, candidates[best])
+		nearest[best] = -1
+		for i := range candidates {
+			if d := labs[i].distance(labs[best]); nearest[i] >= 0 && d < nearest[i] {
+				nearest[i] = d
+			}
+		}
+	}
+	return colors
+}
+
+// DistinctPrinters returns n Printers with text colors returned by DistinctColors.
+func DistinctPrinters(n int, opts ColorOptions) []*Printer {
+	var printers []*Printer
+	for _, c := range DistinctColors(n, opts) {
+		printers = append(printers, &Printer{style: Style{}.Foreground(c)})
+	}
+	return printers
+}
+
+// lab holds color in CIELAB color space.
+type lab struct {
+	l, a, b float64
+}
+
+// lab converts color to CIELAB color space (D65 white point).
+func (c RGB) lab() lab {
+	r, g, b := c.linear()
+	x := (0.4124*r + 0.3576*g + 0.1805*b) / 0.95047
+	y := 0.2126*r + 0.7152*g + 0.0722*b
+	z := (0.0193*r + 0.1192*g + 0.9505*b) / 1.08883
+	f := func(t float64) float64 {
+		if t > 216.0/24389 {
+			return math.Cbrt(t)
+		}
+		return (24389.0/27*t + 16) / 116
+	}
+	fx, fy, fz := f(x), f(y), f(z)
+	return lab{116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)}
+}
+
+// distance returns CIE76 color difference.
+func (c lab) distance(other lab) float64 {
+	dl, da, db := c.l-other.l, c.a-other.a, c.b-other.b
+	return math.Sqrt(dl*dl + da*da + db*db)
+}
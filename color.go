@@ -0,0 +1,341 @@
+package termtools
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// RGB holds a 24-bit (truecolor) value. It may be passed wherever
+// color identifier of type interface{} is accepted. RGB implements color.Color.
+type RGB struct {
+	R, G, B uint8
+}
+
+// ParseHex parses hex color string in form "#rrggbb" or "#rgb" and returns RGB.
+func ParseHex(s string) (RGB, error) {
+	if len(s) < 1 || s[0] != '#' {
+		return RGB{}, ErrUnknownColor
+	}
+	s = s[1:]
+	switch len(s) {
+	case 3:
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	case 6:
+	default:
+		return RGB{}, ErrUnknownColor
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return RGB{}, ErrUnknownColor
+	}
+	return RGB{uint8(v >> 16), uint8(v >> 8), uint8(v)}, nil
+}
+
+// RGBA implements color.Color interface.
+func (c RGB) RGBA() (r, g, b, a uint32) {
+	r, g, b = uint32(c.R), uint32(c.G), uint32(c.B)
+	return r<<8 | r, g<<8 | g, b<<8 | b, 0xffff
+}
+
+// Hex returns color as string in form "#rrggbb".
+func (c RGB) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// String implements fmt.Stringer. It returns the same as Hex.
+func (c RGB) String() string {
+	return c.Hex()
+}
+
+func rgbFromColor(c color.Color) RGB {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return RGB{n.R, n.G, n.B}
+}
+
+func isHex(s string) bool {
+	return strings.HasPrefix(s, "#")
+}
+
+// colorValue holds parsed color identifier. Escape code for it is produced
+// at the time of output so that color can be downsampled to what
+// current ColorProfile supports.
+type colorValue struct {
+	kind colorKind
+	id   int
+	rgb  RGB
+	// variants hold colors for specific profiles if kind is colorVariants
+	variants *[TrueColor + 1]colorValue
+	// adaptive holds colors for light and dark backgrounds if kind is colorAdaptive
+	adaptive *[2]colorValue
+}
+
+type colorKind int
+
+const (
+	colorUnset    colorKind = iota
+	colorANSI               // one of 16 named colors, id is index in ansiNames
+	colorID                 // color from 256 colors palette, id in range [0;255]
+	colorTrue               // 24-bit color held in rgb
+	colorVariants           // different colors for different profiles
+	colorAdaptive           // different colors for light and dark backgrounds
+)
+
+// CompleteColor holds colors to be used with different color profiles. It may be passed
+// wherever color identifier of type interface{} is accepted. Each field accepts
+// the same values as Printer SetColor method and may be left nil. When rendering with
+// certain profile the color for this profile is used. If it is not set the color for the
+// closest richer profile is downsampled, and if there is none, the closest poorer one is used.
+//
+// CompleteColor allows to pick carefully matching base colors instead of relying on automatic
+// downsampling of truecolor values:
+//
+//	termtools.CompleteColor{TrueColor: "#bf616a", ANSI16: "red"}
+type CompleteColor struct {
+	TrueColor interface{}
+	ANSI256   interface{}
+	ANSI16    interface{}
+}
+
+// AdaptiveColor holds colors to be used on terminals with light and dark backgrounds.
+// It may be passed wherever color identifier of type interface{} is accepted. Both fields
+// accept the same values as Printer SetColor method including CompleteColor. If one of
+// them is nil the other one is used regardless of background.
+//
+// Background of terminal is detected when adaptive color is output for the first time
+// (see HasDarkBackground), so that Printer picks the right variant automatically:
+//
+//	termtools.AdaptiveColor{Light: "#005f87", Dark: "#87d7ff"}
+type AdaptiveColor struct {
+	Light interface{}
+	Dark  interface{}
+}
+
+// ansiNames lists names of 16 base colors in order of their IDs.
+var ansiNames = []string{
+	"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+	"brightblack", "brightred", "brightgreen", "brightyellow", "brightblue", "brightmagenta", "brightcyan", "brightwhite"}
+
+func parseColor(a interface{}) (colorValue, error) {
+	switch v := a.(type) {
+	case int:
+		if v >= 0 && v < 256 {
+			return colorValue{kind: colorID, id: v}, nil
+		}
+	case string:
+		for id, name := range ansiNames {
+			if name == v {
+				return colorValue{kind: colorANSI, id: id}, nil
+			}
+		}
+		if isHex(v) {
+			if c, err := ParseHex(v); err == nil {
+				return colorValue{kind: colorTrue, rgb: c}, nil
+			}
+		}
+	case RGB:
+		return colorValue{kind: colorTrue, rgb: v}, nil
+	case CompleteColor:
+		return parseCompleteColor(v)
+	case AdaptiveColor:
+		return parseAdaptiveColor(v)
+	case color.Color:
+		return colorValue{kind: colorTrue, rgb: rgbFromColor(v)}, nil
+	}
+	return colorValue{}, ErrUnknownColor
+}
+
+func parseCompleteColor(cc CompleteColor) (colorValue, error) {
+	var variants [TrueColor + 1]colorValue
+	set := false
+	for profile, color := range map[ColorProfile]interface{}{TrueColor: cc.TrueColor, ANSI256: cc.ANSI256, ANSI16: cc.ANSI16} {
+		if color == nil {
+			continue
+		}
+		c, err := parseColor(color)
+		if err != nil || c.kind == colorVariants || c.kind == colorAdaptive {
+			return colorValue{}, ErrUnknownColor
+		}
+		variants[profile], set = c, true
+	}
+	if !set {
+		return colorValue{}, ErrUnknownColor
+	}
+	return colorValue{kind: colorVariants, variants: &variants}, nil
+}
+
+func parseAdaptiveColor(ac AdaptiveColor) (colorValue, error) {
+	var adaptive [2]colorValue
+	for i, color := range []interface{}{ac.Light, ac.Dark} {
+		if color == nil {
+			continue
+		}
+		c, err := parseColor(color)
+		if err != nil || c.kind == colorAdaptive {
+			return colorValue{}, ErrUnknownColor
+		}
+		adaptive[i] = c
+	}
+	switch {
+	case !adaptive[0].isSet() && !adaptive[1].isSet():
+		return colorValue{}, ErrUnknownColor
+	case !adaptive[0].isSet():
+		return adaptive[1], nil
+	case !adaptive[1].isSet():
+		return adaptive[0], nil
+	}
+	return colorValue{kind: colorAdaptive, adaptive: &adaptive}, nil
+}
+
+// forBackground returns variant of adaptive color which should be used on dark or light background.
+func (c colorValue) forBackground(dark bool) colorValue {
+	if c.kind != colorAdaptive {
+		return c
+	}
+	if dark {
+		return c.adaptive[1]
+	}
+	return c.adaptive[0]
+}
+
+// forProfile returns color variant which should be used with profile.
+func (c colorValue) forProfile(profile ColorProfile) colorValue {
+	if c.kind == colorAdaptive {
+		c = c.forBackground(HasDarkBackground())
+	}
+	if c.kind != colorVariants {
+		return c
+	}
+	if profile < ANSI16 {
+		profile = ANSI16
+	}
+	for p := profile; p <= TrueColor; p++ {
+		if c.variants[p].isSet() {
+			return c.variants[p]
+		}
+	}
+	for p := profile - 1; p >= ANSI16; p-- {
+		if c.variants[p].isSet() {
+			return c.variants[p]
+		}
+	}
+	return colorValue{}
+}
+
+// isSet reports whether color was set.
+func (c colorValue) isSet() bool {
+	return c.kind != colorUnset
+}
+
+// colorLayer defines what color is applied to: text, background or underline.
+type colorLayer int
+
+const (
+	layerForeground colorLayer = iota
+	layerBackground
+	layerUnderline
+)
+
+// code returns escape sequence for color downsampled to requested profile.
+func (c colorValue) code(profile ColorProfile, layer colorLayer) string {
+	c = c.convert(profile)
+	if layer == layerUnderline {
+		// underline color has no codes for 16 base colors
+		switch c.kind {
+		case colorANSI, colorID:
+			return fmt.Sprintf(UnderlineColorIDTemplate, c.id)
+		case colorTrue:
+			return fmt.Sprintf(UnderlineColorRGBTemplate, c.rgb.R, c.rgb.G, c.rgb.B)
+		}
+		return ""
+	}
+	background := layer == layerBackground
+	switch c.kind {
+	case colorANSI:
+		if c.id >= 8 && BoldAsBright() {
+			if background {
+				return fmt.Sprintf(LegacyBrightBackgroundTemplate, c.id-8)
+			}
+			return fmt.Sprintf(LegacyBrightColorTemplate, c.id-8)
+		}
+		if background {
+			return backgroundMap[ansiNames[c.id]]
+		}
+		return colorMap[ansiNames[c.id]]
+	case colorID:
+		if background {
+			return fmt.Sprintf(BackgroundIDTemplate, c.id)
+		}
+		return fmt.Sprintf(ColorIDTemplate, c.id)
+	case colorTrue:
+		if background {
+			return fmt.Sprintf(BackgroundRGBTemplate, c.rgb.R, c.rgb.G, c.rgb.B)
+		}
+		return fmt.Sprintf(ColorRGBTemplate, c.rgb.R, c.rgb.G, c.rgb.B)
+	}
+	return ""
+}
+
+// convert returns nearest color supported by profile.
+func (c colorValue) convert(profile ColorProfile) colorValue {
+	if profile == NoColor {
+		// background of terminal is not queried if colors are disabled
+		return colorValue{}
+	}
+	c = c.forProfile(profile)
+	switch profile {
+	case ANSI16:
+		switch c.kind {
+		case colorID:
+			if c.id < 16 {
+				return colorValue{kind: colorANSI, id: c.id}
+			}
+			return colorValue{kind: colorANSI, id: NearestANSI16(xtermColors[c.id])}
+		case colorTrue:
+			return colorValue{kind: colorANSI, id: NearestANSI16(c.rgb)}
+		}
+	case ANSI256:
+		if c.kind == colorTrue {
+			return colorValue{kind: colorID, id: NearestANSI256(c.rgb)}
+		}
+	}
+	return c
+}
+
+// rgbValue returns RGB value of color as output with profile. Base colors and colors from
+// 256 colors palette are looked up in default xterm palette.
+func (c colorValue) rgbValue(profile ColorProfile) (RGB, bool) {
+	c = c.convert(profile)
+	switch c.kind {
+	case colorANSI, colorID:
+		return xtermColors[c.id], true
+	case colorTrue:
+		return c.rgb, true
+	}
+	return RGB{}, false
+}
+
+var boldAsBright int32
+
+// SetBoldAsBright switches legacy rendering of 8 bright colors on or off. By default bright
+// colors are rendered with aixterm codes (90-97 for colors and 100-107 for backgrounds).
+// Some old terminals do not support these codes and display color combined with
+// bold attribute as bright. If enabled, bright colors are rendered as base color plus
+// bold attribute (for example "\x1b[31;1m" for "brightred"). Note that in this mode
+// bright backgrounds also make text bold.
+// The setting affects the whole package including GetColorCode and GetBackgroundCode,
+// but not exported constants.
+func SetBoldAsBright(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&boldAsBright, v)
+}
+
+// BoldAsBright reports whether legacy rendering of bright colors is on. See SetBoldAsBright.
+func BoldAsBright() bool {
+	return atomic.LoadInt32(&boldAsBright) == 1
+}
@@ -0,0 +1,439 @@
+package termtools
+
+import (
+	"image/color"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_TrueColorCodes(t *testing.T) {
+	cases := []struct {
+		in   interface{}
+		fg   string
+		bg   string
+		fail bool
+	}{
+		{in: "#1e90ff", fg: "\x1b[38;2;30;144;255m", bg: "\x1b[48;2;30;144;255m"},
+		{in: "#abc", fg: "\x1b[38;2;170;187;204m", bg: "\x1b[48;2;170;187;204m"},
+		{in: RGB{1, 2, 3}, fg: "\x1b[38;2;1;2;3m", bg: "\x1b[48;2;1;2;3m"},
+		{in: color.NRGBA{255, 0, 0, 255}, fg: "\x1b[38;2;255;0;0m", bg: "\x1b[48;2;255;0;0m"},
+		{in: "#12345", fail: true},
+		{in: "#ggg", fail: true},
+		{in: 3.5, fail: true},
+	}
+	for _, c := range cases {
+		fg, err := GetColorCode(c.in)
+		if c.fail {
+			if err != ErrUnknownColor {
+				t.Errorf("GetColorCode(%v): expected ErrUnknownColor, got %v", c.in, err)
+			}
+			var p Printer
+			if err := p.SetColor(c.in); err != ErrFailedToSetColor {
+				t.Errorf("SetColor(%v): expected ErrFailedToSetColor, got %v", c.in, err)
+			}
+			continue
+		}
+		if fg != c.fg || err != nil {
+			t.Errorf("GetColorCode(%v) = %q, %v; want %q", c.in, fg, err, c.fg)
+		}
+		if bg, err := GetBackgroundCode(c.in); bg != c.bg || err != nil {
+			t.Errorf("GetBackgroundCode(%v) = %q, %v; want %q", c.in, bg, err, c.bg)
+		}
+	}
+}
+
+func Test_ProfileDownsampling(t *testing.T) {
+	cases := []struct {
+		color   interface{}
+		profile ColorProfile
+		want    string
+	}{
+		{"#ff0000", TrueColor, "\x1b[38;2;255;0;0m"},
+		{"#ff0000", ANSI256, "\x1b[38;5;196m"},
+		{"#ff0000", ANSI16, BrightRed},
+		{"#800000", ANSI16, Red},
+		{196, ANSI16, BrightRed},
+		{4, ANSI16, Blue},
+		{"blue", ANSI256, Blue},
+		{"#ff0000", NoColor, ""},
+		{RGB{128, 128, 128}, ANSI256, "\x1b[38;5;244m"},
+	}
+	for _, c := range cases {
+		var p Printer
+		p.SetColorProfile(c.profile)
+		if err := p.SetColor(c.color); err != nil {
+			t.Fatal(err)
+		}
+		want := c.want + "x" + Reset
+		if c.want == "" {
+			want = "x"
+		}
+		if got := p.Sprint("x"); got != want {
+			t.Errorf("%v with %v: got %q want %q", c.color, c.profile, got, want)
+		}
+	}
+}
+
+func Test_NearestColor(t *testing.T) {
+	if id := NearestANSI256(RGB{0, 0, 0}); id != 16 {
+		t.Errorf("NearestANSI256(black) = %d", id)
+	}
+	if id := NearestANSI256(RGB{95, 135, 175}); id != 67 {
+		t.Errorf("NearestANSI256(#5f87af) = %d", id)
+	}
+	if id := NearestANSI16(RGB{250, 250, 250}); id != 15 {
+		t.Errorf("NearestANSI16(#fafafa) = %d", id)
+	}
+	env := map[string]string{"TERM": "xterm-256color"}
+	if p := envColorProfile(func(k string) string { return env[k] }); p != ANSI256 {
+		t.Errorf("xterm-256color detected as %v", p)
+	}
+	env["COLORTERM"] = "truecolor"
+	if p := envColorProfile(func(k string) string { return env[k] }); p != TrueColor {
+		t.Errorf("COLORTERM=truecolor detected as %v", p)
+	}
+}
+
+func Test_ColorPolicy(t *testing.T) {
+	cases := []struct {
+		mode ColorMode
+		env  map[string]string
+		tty  bool
+		want ColorProfile
+	}{
+		{ColorAuto, map[string]string{"TERM": "xterm-256color"}, true, ANSI256},
+		{ColorAuto, map[string]string{"TERM": "xterm-256color"}, false, NoColor},
+		{ColorAuto, map[string]string{"TERM": "xterm", "NO_COLOR": "1"}, true, NoColor},
+		{ColorAuto, map[string]string{"TERM": "xterm", "CLICOLOR": "0"}, true, NoColor},
+		{ColorAuto, map[string]string{"TERM": "xterm", "CLICOLOR_FORCE": "1"}, false, ANSI16},
+		{ColorAuto, map[string]string{"TERM": "dumb", "FORCE_COLOR": "3"}, false, TrueColor},
+		{ColorAuto, map[string]string{"TERM": "xterm", "FORCE_COLOR": "1", "NO_COLOR": "1"}, false, NoColor},
+		{ColorAlways, map[string]string{"NO_COLOR": "1"}, false, ANSI16},
+		{ColorNever, map[string]string{"COLORTERM": "truecolor"}, true, NoColor},
+	}
+	for i, c := range cases {
+		if got := colorPolicy(c.mode, func(k string) string { return c.env[k] }, c.tty); got != c.want {
+			t.Errorf("case %d: got %v want %v", i, got, c.want)
+		}
+	}
+	f := ColorFlag()
+	if err := f.Set("never"); err != nil || GetColorMode() != ColorNever {
+		t.Errorf("ColorFlag().Set(never): %v, mode %v", err, GetColorMode())
+	}
+	if Csprint("red", "x") != "x" {
+		t.Error("Csprint styled output in ColorNever mode")
+	}
+	if err := f.Set("sometimes"); err != ErrUnknownColorMode {
+		t.Errorf("ColorFlag().Set(sometimes): %v", err)
+	}
+	SetColorMode(ColorAuto)
+}
+
+func Test_AdaptiveColor(t *testing.T) {
+	defer atomic.StoreInt32(&background, backgroundDetect)
+	c := AdaptiveColor{Light: "#005f87", Dark: CompleteColor{TrueColor: "#87d7ff", ANSI16: "brightcyan"}}
+	SetDarkBackground(true)
+	if got := colorCodeFor(c, ANSI16); got != BrightCyan {
+		t.Errorf("dark background: got %q", got)
+	}
+	SetDarkBackground(false)
+	if got := colorCodeFor(c, TrueColor); got != "\x1b[38;2;0;95;135m" {
+		t.Errorf("light background: got %q", got)
+	}
+	if _, err := parseColor(AdaptiveColor{}); err != ErrUnknownColor {
+		t.Errorf("empty AdaptiveColor: %v", err)
+	}
+	if _, err := parseColor(AdaptiveColor{Dark: AdaptiveColor{Light: "red", Dark: "blue"}}); err != ErrUnknownColor {
+		t.Errorf("nested AdaptiveColor: %v", err)
+	}
+
+	// without DetectBackground or SetDarkBackground only COLORFGBG is used
+	defer os.Setenv("COLORFGBG", os.Getenv("COLORFGBG"))
+	for _, c := range []struct {
+		env  string
+		dark bool
+	}{{"0;15", false}, {"15;0", true}, {"", true}} {
+		os.Setenv("COLORFGBG", c.env)
+		atomic.StoreInt32(&background, backgroundDetect)
+		detectOnce = sync.Once{}
+		if dark := HasDarkBackground(); dark != c.dark {
+			t.Errorf("HasDarkBackground() with COLORFGBG=%q = %v", c.env, dark)
+		}
+	}
+	detectOnce = sync.Once{}
+}
+
+func Test_BackgroundReplies(t *testing.T) {
+	osc := []struct {
+		reply string
+		want  RGB
+		fail  bool
+	}{
+		{reply: "\x1b]11;rgb:ffff/ffff/ffff\x1b\\\x1b[?62;22c", want: RGB{255, 255, 255}},
+		{reply: "\x1b]11;rgb:1e1e/2020/3030\a", want: RGB{30, 32, 48}},
+		{reply: "\x1b]11;rgb:f/8/0\a", want: RGB{255, 136, 0}},
+		{reply: "\x1b[?1;2c", fail: true},
+		{reply: "\x1b]11;rgb:zz/00/00\a", fail: true},
+	}
+	for _, c := range osc {
+		got, err := parseOSCColor(c.reply)
+		if c.fail != (err != nil) || got != c.want {
+			t.Errorf("parseOSCColor(%q) = %v, %v; want %v", c.reply, got, err, c.want)
+		}
+	}
+	fgbg := []struct {
+		in   string
+		dark bool
+		ok   bool
+	}{
+		{"15;0", true, true},
+		{"0;15", false, true},
+		{"0;default;7", false, true},
+		{"7;8", true, true},
+		{"", false, false},
+		{"15;default", false, false},
+	}
+	for _, c := range fgbg {
+		if dark, ok := parseColorFGBG(c.in); dark != c.dark || ok != c.ok {
+			t.Errorf("parseColorFGBG(%q) = %v, %v", c.in, dark, ok)
+		}
+	}
+	if !hasDeviceAttributes([]byte("\x1b]11;rgb:0/0/0\a\x1b[?64;1;2c")) || hasDeviceAttributes([]byte("\x1b[?64;1")) {
+		t.Error("hasDeviceAttributes failed")
+	}
+}
+
+func Test_ReadTerminalReply(t *testing.T) {
+	master, slave := openPty(t)
+	state, err := MakeCbreak(int(slave.Fd()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer state.Restore()
+	replies := []struct {
+		reply string
+		ok    bool
+	}{
+		{"\x1b]11;rgb:ffff/ffff/ffff\x1b\\\x1b[?62;22c", true},
+		// terminal which does not answer device attributes request
+		{"\x1b]11;rgb:ffff/ffff/ffff\a", true},
+		{"", false},
+	}
+	for _, c := range replies {
+		master.WriteString(c.reply)
+		if reply, ok := readTerminalReply(int(slave.Fd()), 50*time.Millisecond); reply != c.reply || ok != c.ok {
+			t.Errorf("readTerminalReply() = %q, %v; want %q, %v", reply, ok, c.reply, c.ok)
+		}
+	}
+}
+
+func Test_Palette(t *testing.T) {
+	p := XtermPalette()
+	if c, err := p.RGB(196); c != (RGB{255, 0, 0}) || err != nil {
+		t.Errorf("RGB(196) = %v, %v", c, err)
+	}
+	if _, err := p.RGB(256); err != ErrUnknownColor {
+		t.Errorf("RGB(256): %v", err)
+	}
+	if id := p.Nearest(RGB{250, 5, 5}); id != 9 {
+		t.Errorf("Nearest: got %d", id)
+	}
+	if id, err := CubeID(5, 0, 0); id != 196 || err != nil {
+		t.Errorf("CubeID(5, 0, 0) = %d, %v", id, err)
+	}
+	if r, g, b, ok := CubeCoordinates(110); r != 2 || g != 3 || b != 4 || !ok {
+		t.Errorf("CubeCoordinates(110) = %d, %d, %d, %v", r, g, b, ok)
+	}
+	if step, ok := GrayscaleStep(244); step != 12 || !ok {
+		t.Errorf("GrayscaleStep(244) = %d, %v", step, ok)
+	}
+	if _, ok := GrayscaleStep(231); ok {
+		t.Error("GrayscaleStep(231) succeeded")
+	}
+	cases := []struct {
+		code string
+		id   int
+		name string
+	}{
+		{Red, 1, "red"},
+		{BrightWhite, 15, "brightwhite"},
+		{BBrightBlue, 12, "brightblue"},
+		{"\x1b[31;1m", 9, "brightred"},
+		{"\x1b[38;5;208m", 208, ""},
+		{"\x1b[58;5;3m", 3, "yellow"},
+		{"\x1b[48;2;255;0;0m", 9, "brightred"},
+	}
+	for _, c := range cases {
+		if id, name, err := p.Lookup(c.code); id != c.id || name != c.name || err != nil {
+			t.Errorf("Lookup(%q) = %d, %q, %v", c.code, id, name, err)
+		}
+	}
+	for _, code := range []string{Bold, Reset, "\x1b[91;1m", "\x1b[38;5;256m", "red"} {
+		if _, _, err := p.Lookup(code); err != ErrUnknownColor {
+			t.Errorf("Lookup(%q): %v", code, err)
+		}
+	}
+	for id := 0; id < 256; id++ {
+		code, _ := GetColorCode(id)
+		if got, _, err := p.Lookup(code); got != id || err != nil {
+			t.Errorf("Lookup(GetColorCode(%d)) = %d, %v", id, got, err)
+		}
+	}
+}
+
+func Test_ColorMath(t *testing.T) {
+	colors := []RGB{{0, 0, 0}, {255, 255, 255}, {255, 0, 0}, {30, 144, 255}, {128, 64, 200}, {10, 200, 90}}
+	for _, c := range colors {
+		if got := c.HSL().RGB(); got != c {
+			t.Errorf("HSL round trip of %v: got %v", c, got)
+		}
+		if got := c.HSV().RGB(); got != c {
+			t.Errorf("HSV round trip of %v: got %v", c, got)
+		}
+	}
+	if hsl := (RGB{255, 0, 0}).HSL(); hsl != (HSL{0, 1, 0.5}) {
+		t.Errorf("HSL of red: %v", hsl)
+	}
+	if hsv := (RGB{0, 0, 255}).HSV(); hsv != (HSV{240, 1, 1}) {
+		t.Errorf("HSV of blue: %v", hsv)
+	}
+	if c := (RGB{255, 0, 0}).Lighten(0.25); c != (RGB{255, 128, 128}) {
+		t.Errorf("Lighten: %v", c)
+	}
+	if c := (RGB{255, 0, 0}).Darken(0.25); c != (RGB{128, 0, 0}) {
+		t.Errorf("Darken: %v", c)
+	}
+	if c := (RGB{255, 0, 0}).Desaturate(1); c != (RGB{128, 128, 128}) {
+		t.Errorf("Desaturate: %v", c)
+	}
+	if c := Blend(RGB{0, 0, 0}, RGB{255, 100, 50}, 0.5); c != (RGB{128, 50, 25}) {
+		t.Errorf("Blend: %v", c)
+	}
+	if r := ContrastRatio(RGB{0, 0, 0}, RGB{255, 255, 255}); r != 21 {
+		t.Errorf("ContrastRatio(black, white) = %v", r)
+	}
+	if r := ContrastRatio(RGB{118, 118, 118}, RGB{255, 255, 255}); r < 4.5 || r > 4.6 {
+		t.Errorf("ContrastRatio(#767676, white) = %v", r)
+	}
+	if c := ReadableForeground(RGB{0, 0, 128}); c != (RGB{255, 255, 255}) {
+		t.Errorf("ReadableForeground(navy) = %v", c)
+	}
+	if c := ReadableForeground(RGB{255, 255, 0}); c != (RGB{0, 0, 0}) {
+		t.Errorf("ReadableForeground(yellow) = %v", c)
+	}
+	if code := colorCodeFor(HSL{120, 1, 0.5}, TrueColor); code != "\x1b[38;2;0;255;0m" {
+		t.Errorf("HSL as color: %q", code)
+	}
+
+	var p Printer
+	p.SetColorProfile(ANSI16)
+	p.SetBackground("yellow")
+	p.ToggleAutoForeground()
+	if got := p.Sprint("x"); got != Black+BYellow+"x"+Reset {
+		t.Errorf("auto foreground on yellow: %q", got)
+	}
+	p.SetBackground(4)
+	if got := p.Sprint("x"); got != BrightWhite+BBlue+"x"+Reset {
+		t.Errorf("auto foreground on blue: %q", got)
+	}
+	p.SetColor("red")
+	if got := p.Sprint("x"); got != Red+BBlue+"x"+Reset {
+		t.Errorf("auto foreground with color set: %q", got)
+	}
+	if conf, err := ParseStyle("autofg on blue"); err != nil || !conf.AutoForeground || conf.String() != "autofg on blue" {
+		t.Errorf("ParseStyle(autofg on blue) = %v, %v", conf, err)
+	}
+	// auto foreground is not an attribute and is inherited separately
+	parent := Style{}.Background("yellow").AutoForeground(true)
+	if s := parent.Set(allAttributes, false); s.attrs != 0 || !s.autoForeground {
+		t.Errorf("switching attributes off changed auto foreground: %+v", s)
+	}
+	if s := (Style{}).Bold().Inherit(parent); !s.autoForeground || s.attrs != AttrBold {
+		t.Errorf("auto foreground not inherited: %+v", s)
+	}
+	if s := (Style{}).AutoForeground(false).Inherit(parent); s.autoForeground || s.codes(ANSI16) != BYellow {
+		t.Errorf("auto foreground switched off is inherited: %+v", s)
+	}
+}
+
+func Test_CategoricalColors(t *testing.T) {
+	dark := ColorOptions{Background: "#000000"}
+	colors := DistinctColors(8, dark)
+	if len(colors) != 8 {
+		t.Fatalf("DistinctColors(8) returned %d colors", len(colors))
+	}
+	seen := map[RGB]bool{}
+	for _, c := range colors {
+		if seen[c] || ContrastRatio(c, RGB{}) < DefaultMinContrast || XtermPalette()[XtermPalette().Nearest(c)] != c {
+			t.Errorf("DistinctColors: bad color %v in %v", c, colors)
+		}
+		seen[c] = true
+	}
+	if got := DistinctColors(8, dark); got[7] != colors[7] {
+		t.Error("DistinctColors is not deterministic")
+	}
+	opts := ColorOptions{Colors: []RGB{{250, 0, 0}, {255, 0, 0}, {0, 0, 255}, {0, 0, 0}}, Background: "white", MinContrast: -1}
+	if got := DistinctColors(3, opts); len(got) != 3 || got[0] != (RGB{0, 0, 255}) || got[1] != (RGB{255, 0, 0}) || got[2] != (RGB{0, 0, 0}) {
+		t.Errorf("DistinctColors with candidates: %v", got)
+	}
+	for _, key := range []string{"worker-1", "worker-2", "db.example.com"} {
+		c := ColorFor(key, dark)
+		if c != ColorFor(key, dark) || ContrastRatio(c, RGB{}) < DefaultMinContrast {
+			t.Errorf("ColorFor(%q) = %v", key, c)
+		}
+		if c := ColorFor(key, ColorOptions{Background: "white", Colors: []RGB{{255, 255, 0}, {0, 0, 128}}}); c != (RGB{0, 0, 128}) {
+			t.Errorf("ColorFor(%q) picked unreadable color %v", key, c)
+		}
+	}
+	p := DistinctPrinters(2, dark)
+	p[0].SetColorProfile(TrueColor)
+	if len(p) != 2 || p[0].Sprint("x") == "x" {
+		t.Errorf("DistinctPrinters: %v", p)
+	}
+}
+
+func Test_Scale(t *testing.T) {
+	s, err := NewScale("grayscale", 0, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		v    float64
+		want RGB
+	}{{0, RGB{0, 0, 0}}, {50, RGB{128, 128, 128}}, {100, RGB{255, 255, 255}}, {-5, RGB{0, 0, 0}}, {1e9, RGB{255, 255, 255}}}
+	for _, c := range cases {
+		if got := s.Color(c.v); got != c.want {
+			t.Errorf("Color(%v) = %v want %v", c.v, got, c.want)
+		}
+	}
+	if code, _ := GetColorCode(RGB{128, 128, 128}); s.Code(50) != code {
+		t.Errorf("Code(50) = %q want %q", s.Code(50), code)
+	}
+	if code, _ := GetBackgroundCode(RGB{255, 255, 255}); s.BackgroundCode(100) != code {
+		t.Errorf("BackgroundCode(100) = %q want %q", s.BackgroundCode(100), code)
+	}
+	reversed, _ := NewScale("red-yellow-green", 500, 0)
+	if got := reversed.Color(0); got != (RGB{0, 104, 55}) {
+		t.Errorf("reversed scale: Color(0) = %v", got)
+	}
+	d, _ := NewDivergingScale("red-blue", -10, 0, 100)
+	for v, want := range map[float64]RGB{-10: {103, 0, 31}, -6: {214, 96, 77}, 0: {247, 247, 247}, 40: {146, 197, 222}, 100: {5, 48, 97}} {
+		if got := d.Color(v); got != want {
+			t.Errorf("diverging Color(%v) = %v want %v", v, got, want)
+		}
+	}
+	p := d.BackgroundPrinter(-10)
+	p.SetColorProfile(ANSI256)
+	if got := p.Sprint("x"); got != "\x1b[38;5;231m\x1b[48;5;52mx"+Reset {
+		t.Errorf("BackgroundPrinter: %q", got)
+	}
+	if _, err := NewScale("jet", 0, 1); err != ErrUnknownColormap {
+		t.Errorf("NewScale(jet): %v", err)
+	}
+	if len(Colormaps()) != 5 {
+		t.Errorf("Colormaps: %v", Colormaps())
+	}
+}
@@ -0,0 +1,216 @@
+package termtools
+
+import "math"
+
+// HSL represents color in HSL (hue, saturation, lightness) color space. Hue is in degrees
+// in range [0;360), saturation and lightness are in range [0;1].
+// HSL implements color.Color, so it may be passed wherever color identifier is accepted.
+type HSL struct {
+	H, S, L float64
+}
+
+// HSV represents color in HSV (hue, saturation, value) color space. Hue is in degrees
+// in range [0;360), saturation and value are in range [0;1].
+// HSV implements color.Color, so it may be passed wherever color identifier is accepted.
+type HSV struct {
+	H, S, V float64
+}
+
+var (
+	black = RGB{0, 0, 0}
+	white = RGB{255, 255, 255}
+)
+
+// HSL converts color to HSL color space.
+func (c RGB) HSL() HSL {
+	r, g, b := c.unit()
+	max, min := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	h := hue(r, g, b, max, min)
+	l := (max + min) / 2
+	var s float64
+	if d := max - min; d > 0 {
+		s = d / (1 - math.Abs(2*l-1))
+	}
+	return HSL{h, clampUnit(s), l}
+}
+
+// HSV converts color to HSV color space.
+func (c RGB) HSV() HSV {
+	r, g, b := c.unit()
+	max, min := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	var s float64
+	if max > 0 {
+		s = (max - min) / max
+	}
+	return HSV{hue(r, g, b, max, min), s, max}
+}
+
+// unit returns color components scaled to range [0;1].
+func (c RGB) unit() (r, g, b float64) {
+	return float64(c.R) / 255, float64(c.G) / 255, float64(c.B) / 255
+}
+
+func hue(r, g, b, max, min float64) float64 {
+	d := max - min
+	if d == 0 {
+		return 0
+	}
+	var h float64
+	switch max {
+	case r:
+		h = math.Mod((g-b)/d, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+// RGB converts color to RGB.
+func (c HSL) RGB() RGB {
+	s, l := clampUnit(c.S), clampUnit(c.L)
+	chroma := (1 - math.Abs(2*l-1)) * s
+	return fromHueChroma(c.H, chroma, l-chroma/2)
+}
+
+// RGBA implements color.Color interface.
+func (c HSL) RGBA() (r, g, b, a uint32) {
+	return c.RGB().RGBA()
+}
+
+// RGB converts color to RGB.
+func (c HSV) RGB() RGB {
+	s, v := clampUnit(c.S), clampUnit(c.V)
+	chroma := v * s
+	return fromHueChroma(c.H, chroma, v-chroma)
+}
+
+// RGBA implements color.Color interface.
+func (c HSV) RGBA() (r, g, b, a uint32) {
+	return c.RGB().RGBA()
+}
+
+// fromHueChroma returns RGB color with hue h and chroma c lifted by m.
+func fromHueChroma(h, c, m float64) RGB {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	h /= 60
+	x := c * (1 - math.Abs(math.Mod(h, 2)-1))
+	var r, g, b float64
+	switch int(h) {
+	case 0:
+		r, g, b = c, x, 0
+	case 1:
+		r, g, b = x, c, 0
+	case 2:
+		r, g, b = 0, c, x
+	case 3:
+		r, g, b = 0, x, c
+	case 4:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	return RGB{unitToByte(r + m), unitToByte(g + m), unitToByte(b + m)}
+}
+
+func clampUnit(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+func unitToByte(v float64) uint8 {
+	return uint8(math.Round(clampUnit(v) * 255))
+}
+
+// Lighten returns color with lightness (in HSL color space) increased by amount in range [0;1].
+// Negative amount darkens color.
+func (c RGB) Lighten(amount float64) RGB {
+	hsl := c.HSL()
+	hsl.L = clampUnit(hsl.L + amount)
+	return hsl.RGB()
+}
+
+// Darken returns color with lightness (in HSL color space) decreased by amount in range [0;1].
+func (c RGB) Darken(amount float64) RGB {
+	return c.Lighten(-amount)
+}
+
+// Saturate returns color with saturation (in HSL color space) increased by amount in range [0;1].
+// Negative amount desaturates color.
+func (c RGB) Saturate(amount float64) RGB {
+	hsl := c.HSL()
+	hsl.S = clampUnit(hsl.S + amount)
+	return hsl.RGB()
+}
+
+// Desaturate returns color with saturation (in HSL color space) decreased by amount in range [0;1].
+func (c RGB) Desaturate(amount float64) RGB {
+	return c.Saturate(-amount)
+}
+
+// Blend mixes colors a and b. Argument t in range [0;1] is the share of b:
+// 0 returns a, 1 returns b and 0.5 returns color halfway between them.
+func Blend(a, b RGB, t float64) RGB {
+	t = clampUnit(t)
+	mix := func(x, y uint8) uint8 {
+		return uint8(math.Round(float64(x) + (float64(y)-float64(x))*t))
+	}
+	return RGB{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B)}
+}
+
+// Luminance returns relative luminance of color as defined by WCAG 2.0:
+// 0 for black and 1 for white.
+func (c RGB) Luminance() float64 {
+	r, g, b := c.linear()
+	return 0.2126*r + 0.7152*g + 0.0722*b
+}
+
+// linear returns color components converted from sRGB to linear RGB in range [0;1].
+func (c RGB) linear() (r, g, b float64) {
+	linear := func(v uint8) float64 {
+		s := float64(v) / 255
+		// threshold as given by WCAG 2.0, no 8-bit value falls between it and 0.04045 of sRGB specification
+		if s <= 0.03928 {
+			return s / 12.92
+		}
+		return math.Pow((s+0.055)/1.055, 2.4)
+	}
+	return linear(c.R), linear(c.G), linear(c.B)
+}
+
+// fromLinear converts color from linear RGB to sRGB. Components out of range [0;1] are clamped.
+func fromLinear(r, g, b float64) RGB {
+	srgb := func(v float64) uint8 {
+		v = clampUnit(v)
+		if v <= 0.0031308 {
+			return unitToByte(v * 12.92)
+		}
+		return unitToByte(1.055*math.Pow(v, 1/2.4) - 0.055)
+	}
+	return RGB{srgb(r), srgb(g), srgb(b)}
+}
+
+// ContrastRatio returns WCAG 2.0 contrast ratio of two colors in range [1;21]. The order
+// of arguments does not matter. WCAG recommends ratio of at least 4.5 for normal text.
+func ContrastRatio(a, b RGB) float64 {
+	la, lb := a.Luminance(), b.Luminance()
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// ReadableForeground returns black or white whichever has higher contrast with background bg.
+func ReadableForeground(bg RGB) RGB {
+	if ContrastRatio(bg, white) > ContrastRatio(bg, black) {
+		return white
+	}
+	return black
+}
@@ -0,0 +1,84 @@
+package termtools
+
+import (
+	"errors"
+	"flag"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrUnknownColorMode is returned when parsing invalid color mode.
+var ErrUnknownColorMode = errors.New("error: unknown color mode: must be one of auto, always, never")
+
+// ColorMode defines whether output is styled. See DetectColorProfile for details
+// of how mode is applied.
+type ColorMode int
+
+const (
+	// ColorAuto styles output only if it goes to a terminal and environment permits colors.
+	ColorAuto ColorMode = iota
+	// ColorAlways styles output regardless of output destination and environment.
+	ColorAlways
+	// ColorNever disables styling.
+	ColorNever
+)
+
+var colorModeNames = []string{"auto", "always", "never"}
+
+// String implements fmt.Stringer. It returns "auto", "always" or "never".
+func (mode ColorMode) String() string {
+	if mode >= 0 && int(mode) < len(colorModeNames) {
+		return colorModeNames[mode]
+	}
+	return "unknown"
+}
+
+// ParseColorMode accepts "auto", "always" or "never" (case insensitive) and returns
+// corresponding ColorMode. Empty string is parsed as ColorAuto.
+func ParseColorMode(s string) (ColorMode, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return ColorAuto, nil
+	}
+	for mode, name := range colorModeNames {
+		if name == s {
+			return ColorMode(mode), nil
+		}
+	}
+	return ColorAuto, ErrUnknownColorMode
+}
+
+var currentColorMode int32
+
+// SetColorMode sets color mode for the whole package. Default mode is ColorAuto.
+func SetColorMode(mode ColorMode) {
+	atomic.StoreInt32(&currentColorMode, int32(mode))
+}
+
+// GetColorMode returns currently used color mode.
+func GetColorMode() ColorMode {
+	return ColorMode(atomic.LoadInt32(&currentColorMode))
+}
+
+// ColorFlag returns flag.Value which sets color mode of the package when parsed. It accepts
+// "auto", "always" and "never". Use it to add --color option to your program:
+//
+//	flag.Var(termtools.ColorFlag(), "color", "colorize output: auto, always or never")
+func ColorFlag() flag.Value {
+	return colorFlag{}
+}
+
+type colorFlag struct{}
+
+func (colorFlag) String() string {
+	return GetColorMode().String()
+}
+
+func (colorFlag) Set(s string) error {
+	mode, err := ParseColorMode(s)
+	if err != nil {
+		return err
+	}
+	SetColorMode(mode)
+	return nil
+}
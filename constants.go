@@ -16,15 +16,15 @@ const (
 	Cyan           = Esc + "[36m"
 	White          = Esc + "[37m"
 
-	//Additional 8 bright colors
-	BrightBlack   string = Esc + "[30;1m"
-	BrightRed            = Esc + "[31;1m"
-	BrightGreen          = Esc + "[32;1m"
-	BrightYellow         = Esc + "[33;1m"
-	BrightBlue           = Esc + "[34;1m"
-	BrightMagenta        = Esc + "[35;1m"
-	BrightCyan           = Esc + "[36;1m"
-	BrightWhite          = Esc + "[37;1m"
+	//Additional 8 bright colors (aixterm codes)
+	BrightBlack   string = Esc + "[90m"
+	BrightRed            = Esc + "[91m"
+	BrightGreen          = Esc + "[92m"
+	BrightYellow         = Esc + "[93m"
+	BrightBlue           = Esc + "[94m"
+	BrightMagenta        = Esc + "[95m"
+	BrightCyan           = Esc + "[96m"
+	BrightWhite          = Esc + "[97m"
 
 	//Basic 8 background colors
 	BBlack   string = Esc + "[40m"
@@ -36,25 +36,53 @@ const (
 	BCyan           = Esc + "[46m"
 	BWhite          = Esc + "[47m"
 
-	//Additional 8 bright background colors
-	BBrightBlack   string = Esc + "[40;1m"
-	BBrightRed            = Esc + "[41;1m"
-	BBrightGreen          = Esc + "[42;1m"
-	BBrightYellow         = Esc + "[43;1m"
-	BBrightBlue           = Esc + "[44;1m"
-	BBrightMagenta        = Esc + "[45;1m"
-	BBrightCyan           = Esc + "[46;1m"
-	BBrightWhite          = Esc + "[47;1m"
+	//Additional 8 bright background colors (aixterm codes)
+	BBrightBlack   string = Esc + "[100m"
+	BBrightRed            = Esc + "[101m"
+	BBrightGreen          = Esc + "[102m"
+	BBrightYellow         = Esc + "[103m"
+	BBrightBlue           = Esc + "[104m"
+	BBrightMagenta        = Esc + "[105m"
+	BBrightCyan           = Esc + "[106m"
+	BBrightWhite          = Esc + "[107m"
+
+	// Legacy format strings for bright colors where bold attribute is used to make
+	// color bright. Needs int in range [0;7]. See SetBoldAsBright.
+	LegacyBrightColorTemplate      string = Esc + "[3%v;1m"
+	LegacyBrightBackgroundTemplate        = Esc + "[4%v;1m"
 
 	// Color format string to use with 256 color codes. Needs int in range [0;255].
 	ColorIDTemplate      string = Esc + "[38;5;%vm"
 	BackgroundIDTemplate        = Esc + "[48;5;%vm"
 
+	// Color format string to use with 24-bit (truecolor) values. Needs red, green and blue
+	// components each in range [0;255].
+	ColorRGBTemplate      string = Esc + "[38;2;%v;%v;%vm"
+	BackgroundRGBTemplate        = Esc + "[48;2;%v;%v;%vm"
+
+	// Color format strings to set color of underline. Not supported by all terminals.
+	// UnderlineColorIDTemplate needs int in range [0;255], UnderlineColorRGBTemplate needs red, green
+	// and blue components each in range [0;255].
+	UnderlineColorIDTemplate  string = Esc + "[58;5;%vm"
+	UnderlineColorRGBTemplate        = Esc + "[58;2;%v;%v;%vm"
+
 	//Styles. Can be used separately or together with color and background codes.
-	Bold      string = Esc + "[1m"
-	Underline        = Esc + "[4m"
-	Blinking         = Esc + "[5m"
-	Reversed         = Esc + "[7m"
+	Bold          string = Esc + "[1m"
+	Faint                = Esc + "[2m"
+	Italic               = Esc + "[3m"
+	Underline            = Esc + "[4m"
+	Blinking             = Esc + "[5m"
+	Reversed             = Esc + "[7m"
+	Hidden               = Esc + "[8m"
+	Strikethrough        = Esc + "[9m"
+	Overline             = Esc + "[53m"
+
+	// Underline styles. Not supported by all terminals (those which do not
+	// support these may ignore them or fall back to single underline).
+	DoubleUnderline string = Esc + "[4:2m"
+	CurlyUnderline         = Esc + "[4:3m"
+	DottedUnderline        = Esc + "[4:4m"
+	DashedUnderline        = Esc + "[4:5m"
 
 	// Reset escape sequence
 	Reset string = Esc + "[0m"
@@ -85,6 +113,32 @@ const (
 	FormFeed string = "\x0c"
 )
 
+// UnderlineStyle defines how text is underlined.
+type UnderlineStyle int
+
+const (
+	// UnderlineSingle is the usual straight underline.
+	UnderlineSingle UnderlineStyle = iota
+	// UnderlineDouble draws two lines under text.
+	UnderlineDouble
+	// UnderlineCurly draws wavy line as used by spell checkers.
+	UnderlineCurly
+	// UnderlineDotted draws dotted line.
+	UnderlineDotted
+	// UnderlineDashed draws dashed line.
+	UnderlineDashed
+)
+
+var underlineStyles = []string{Underline, DoubleUnderline, CurlyUnderline, DottedUnderline, DashedUnderline}
+
+// code returns escape sequence for underline style.
+func (style UnderlineStyle) code() string {
+	if style >= 0 && int(style) < len(underlineStyles) {
+		return underlineStyles[style]
+	}
+	return Underline
+}
+
 var (
 	ErrUnknownColor    = errors.New("error: unknown color name or color id out of range [0;255]")
 	ErrUnknownTermSize = errors.New("error: could not find out terminal size")
@@ -128,8 +182,13 @@ var (
 		"brightcyan":    BBrightCyan,
 		"brightwhite":   BBrightWhite}
 	modeMap = map[string]string{
-		"bold":      Bold,
-		"underline": Underline,
-		"blinking":  Blinking,
-		"reversed":  Reversed}
+		"bold":          Bold,
+		"faint":         Faint,
+		"italic":        Italic,
+		"underline":     Underline,
+		"blinking":      Blinking,
+		"reversed":      Reversed,
+		"hidden":        Hidden,
+		"strikethrough": Strikethrough,
+		"overline":      Overline}
 )
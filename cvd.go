@@ -0,0 +1,61 @@
+package termtools
+
+// Vision is a kind of color vision used to simulate how colors are seen by people with
+// color vision deficiency (color blindness).
+type Vision int
+
+const (
+	// NormalVision is vision without deficiency.
+	NormalVision Vision = iota
+	// Protanopia is inability to perceive red light.
+	Protanopia
+	// Deuteranopia is inability to perceive green light.
+	Deuteranopia
+	// Tritanopia is inability to perceive blue light.
+	Tritanopia
+)
+
+var visionNames = map[Vision]string{
+	NormalVision: "normal vision",
+	Protanopia:   "protanopia",
+	Deuteranopia: "deuteranopia",
+	Tritanopia:   "tritanopia"}
+
+// String implements fmt.Stringer.
+func (v Vision) String() string {
+	if name, ok := visionNames[v]; ok {
+		return name
+	}
+	return "Vision(unknown)"
+}
+
+// cvdMatrices hold matrices simulating full color vision deficiencies in linear RGB
+// as proposed by Machado, Oliveira and Fernandes (2009).
+var cvdMatrices = map[Vision][3][3]float64{
+	Protanopia: {
+		{0.152286, 1.052583, -0.204868},
+		{0.114503, 0.786281, 0.099216},
+		{-0.003882, -0.048116, 1.051998}},
+	Deuteranopia: {
+		{0.367322, 0.860646, -0.227968},
+		{0.280085, 0.672501, 0.047413},
+		{-0.011820, 0.042940, 0.968881}},
+	Tritanopia: {
+		{1.255528, -0.076749, -0.178779},
+		{-0.078411, 0.930809, 0.146390},
+		{0.004733, 0.691367, 0.303900}},
+}
+
+// SimulateCVD returns color c as seen with vision v. For NormalVision (and unknown values of v)
+// c is returned unchanged.
+func SimulateCVD(c RGB, v Vision) RGB {
+	m, ok := cvdMatrices[v]
+	if !ok {
+		return c
+	}
+	r, g, b := c.linear()
+	return fromLinear(
+		m[0][0]*r+m[0][1]*g+m[0][2]*b,
+		m[1][0]*r+m[1][1]*g+m[1][2]*b,
+		m[2][0]*r+m[2][1]*g+m[2][2]*b)
+}
@@ -0,0 +1,200 @@
+package termtools
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// csiKeys maps final bytes of CSI and SS3 sequences to keys.
+var csiKeys = map[byte]Key{
+	'A': KeyUp, 'B': KeyDown, 'C': KeyRight, 'D': KeyLeft, 'H': KeyHome, 'F': KeyEnd,
+	'P': KeyF1, 'Q': KeyF2, 'R': KeyF3, 'S': KeyF4}
+
+// tildeKeys maps numbers of CSI sequences ending with "~" to keys.
+var tildeKeys = map[int]Key{
+	1: KeyHome, 2: KeyInsert, 3: KeyDelete, 4: KeyEnd, 5: KeyPageUp, 6: KeyPageDown, 7: KeyHome, 8: KeyEnd,
+	11: KeyF1, 12: KeyF2, 13: KeyF3, 14: KeyF4, 15: KeyF5, 17: KeyF6, 18: KeyF7, 19: KeyF8,
+	20: KeyF9, 21: KeyF10, 23: KeyF11, 24: KeyF12}
+
+// decodeEvent decodes the first event in b and returns it with number of bytes consumed.
+// If b starts with incomplete sequence decodeEvent returns 0 unless final is true, in which
+// case incomplete sequence is decoded as is (lone ESC is Escape key). Unknown sequences
+// are consumed and reported as nil event.
+func decodeEvent(b []byte, final bool) (Event, int) {
+	if len(b) == 0 {
+		return nil, 0
+	}
+	if b[0] != 0x1b {
+		return decodeKey(b, final)
+	}
+	if len(b) == 1 {
+		if final {
+			return KeyEvent{Key: KeyEscape}, 1
+		}
+		return nil, 0
+	}
+	switch b[1] {
+	case '[':
+		if ev, n, ok := decodeCSI(b, final); ok {
+			return ev, n
+		}
+	case 'O':
+		if len(b) < 3 {
+			if !final {
+				return nil, 0
+			}
+			break
+		}
+		if key, ok := csiKeys[b[2]]; ok {
+			return KeyEvent{Key: key}, 3
+		}
+		return nil, 3
+	}
+	// ESC followed by another key is Alt combination
+	ev, n := decodeEvent(b[1:], final)
+	if n == 0 {
+		return nil, 0
+	}
+	if key, ok := ev.(KeyEvent); ok {
+		key.Mod |= ModAlt
+		return key, n + 1
+	}
+	return ev, n + 1
+}
+
+// decodeKey decodes a character or control key.
+func decodeKey(b []byte, final bool) (Event, int) {
+	c := b[0]
+	switch {
+	case c == '\r' || c == '\n':
+		return KeyEvent{Key: KeyEnter}, 1
+	case c == '\t':
+		return KeyEvent{Key: KeyTab}, 1
+	case c == 0x7f || c == 0x08:
+		return KeyEvent{Key: KeyBackspace}, 1
+	case c == 0:
+		return KeyEvent{Key: KeyRune, Rune: ' ', Mod: ModCtrl}, 1
+	case c < 0x1b:
+		return KeyEvent{Key: KeyRune, Rune: rune('a' + c - 1), Mod: ModCtrl}, 1
+	case c < 0x20:
+		// Ctrl+\, Ctrl+], Ctrl+^ and Ctrl+_
+		return KeyEvent{Key: KeyRune, Rune: rune(c + 0x40), Mod: ModCtrl}, 1
+	}
+	if !utf8.FullRune(b) && !final {
+		return nil, 0
+	}
+	r, n := utf8.DecodeRune(b)
+	return KeyEvent{Key: KeyRune, Rune: r}, n
+}
+
+// decodeCSI decodes sequence starting with "\x1b[". If b does not hold valid sequence ok is false.
+func decodeCSI(b []byte, final bool) (ev Event, n int, ok bool) {
+	if len(b) > 2 && b[2] == '[' {
+		// Linux console function keys: "\x1b[[A" to "\x1b[[E"
+		switch {
+		case len(b) == 3:
+			return nil, 0, !final
+		case b[3] >= 'A' && b[3] <= 'E':
+			return KeyEvent{Key: KeyF1 + Key(b[3]-'A')}, 4, true
+		}
+		return nil, 0, false
+	}
+	if len(b) > 2 && b[2] == 'M' {
+		// legacy mouse report: "\x1b[M" followed by three bytes holding button and coordinates plus 32
+		if len(b) < 6 {
+			return nil, 0, !final
+		}
+		return decodeMouse(int(b[3])-32, int(b[4])-32, int(b[5])-32, false), 6, true
+	}
+	end := 2
+	for ; end < len(b); end++ {
+		c := b[end]
+		if c >= 0x40 && c <= 0x7e {
+			break
+		}
+		if c < 0x20 || c > 0x3f {
+			return nil, 0, false
+		}
+	}
+	if end == len(b) {
+		if final {
+			return nil, 0, false
+		}
+		return nil, 0, true
+	}
+	n = end + 1
+	params, finalByte := string(b[2:end]), b[end]
+	switch {
+	case strings.HasPrefix(params, "<") && (finalByte == 'M' || finalByte == 'm'):
+		// SGR mouse report: "\x1b[<button;x;yM", final byte "m" marks release
+		fields := csiParams(params[1:])
+		return decodeMouse(fields.get(0, 0), fields.get(1, 1), fields.get(2, 1), finalByte == 'm'), n, true
+	case finalByte == '~' && params == "200":
+		ev, n := decodePaste(b, n, final)
+		return ev, n, true
+	case (finalByte == 'I' || finalByte == 'O') && params == "":
+		return FocusEvent{Focused: finalByte == 'I'}, n, true
+	case finalByte == '~':
+		fields := csiParams(params)
+		if key, ok := tildeKeys[fields.get(0, 0)]; ok {
+			return KeyEvent{Key: key, Mod: fields.modifier(1), Type: fields.eventType(1)}, n, true
+		}
+	case finalByte == 'u' && params != "" && params[0] >= '0' && params[0] <= '9':
+		// kitty keyboard protocol, reply to flags query "\x1b[?flagsu" is skipped
+		return decodeKittyKey(csiParams(params)), n, true
+	case finalByte == 'Z' && params == "":
+		return KeyEvent{Key: KeyTab, Mod: ModShift}, n, true
+	default:
+		if key, ok := csiKeys[finalByte]; ok {
+			fields := csiParams(params)
+			return KeyEvent{Key: key, Mod: fields.modifier(1), Type: fields.eventType(1)}, n, true
+		}
+	}
+	return nil, n, true
+}
+
+// csiParams holds numeric parameters of CSI sequence separated by ";". Parameters
+// may consist of sub-parameters separated by ":".
+type csiParams string
+
+// get returns i-th parameter (its first sub-parameter) or def if it is missing or invalid.
+func (p csiParams) get(i, def int) int {
+	return p.sub(i, 0, def)
+}
+
+// sub returns j-th sub-parameter of i-th parameter or def if it is missing or invalid.
+func (p csiParams) sub(i, j, def int) int {
+	fields := strings.Split(string(p), ";")
+	if i >= len(fields) {
+		return def
+	}
+	subfields := strings.Split(fields[i], ":")
+	if j >= len(subfields) {
+		return def
+	}
+	v, err := strconv.Atoi(subfields[j])
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// modifier returns modifiers encoded in i-th parameter as 1 + bitmask of modifiers.
+func (p csiParams) modifier(i int) Modifier {
+	if m := p.get(i, 1); m > 1 {
+		return Modifier(m - 1)
+	}
+	return 0
+}
+
+// eventType returns type of key event encoded in sub-parameter of i-th parameter by kitty keyboard protocol.
+func (p csiParams) eventType(i int) KeyEventType {
+	switch p.sub(i, 1, 1) {
+	case 2:
+		return KeyRepeat
+	case 3:
+		return KeyRelease
+	}
+	return KeyPress
+}
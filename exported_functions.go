@@ -8,26 +8,23 @@
 //
 // General note concerning module usage:
 // Whenever color value of type interface{} is required by signature
-// of a method or function, either string or int may be supplied.
+// of a method or function, either string, int, RGB or color.Color may be supplied.
 // Valid color names (to be passed as string) are: "black", "blue", "cyan", "green", "magenta",
 // "red", "white", "yellow", "brightblack", "brightblue", "brightcyan",
 // "brightgreen", "brightmagenta", "brightred", "brightwhite", "brightyellow".
 // Valid color IDs (to be passed as int) are from 0 to 255 inclusive.
+// Hex strings like "#1e90ff" or "#abc", RGB and color.Color values set 24-bit (truecolor) colors.
 //
 package termtools
 
-import (
-	"fmt"
-)
-
-// GetColorCode accepts color identifier (string or int) and returns ANSI escape sequence
+// GetColorCode accepts color identifier (string, int, RGB or color.Color) and returns ANSI escape sequence
 // for requested color. If color is invalid the function will return
 // empty string and an error.
 func GetColorCode(color interface{}) (string, error) {
 	return getColorCode(color)
 }
 
-// GetBackgroundCode accepts color identifier (string or int) and returns ANSI escape sequence
+// GetBackgroundCode accepts color identifier (string, int, RGB or color.Color) and returns ANSI escape sequence
 // for requested background color. If color is invalid the function will return
 // empty string and an error.
 func GetBackgroundCode(color interface{}) (string, error) {
@@ -35,14 +32,14 @@ func GetBackgroundCode(color interface{}) (string, error) {
 }
 
 // Csprint formats using the default formats for its operands and returns the resulting string.
-// It accepts color color identifier (string or int). If color is invalid the function will return
+// It accepts color identifier (string, int, RGB or color.Color). If color is invalid the function will return
 // fmt.Sprint(a).
 func Csprint(color interface{}, a ...interface{}) string {
 	return colorSprint(color, a...)
 }
 
 // Csprintf formats according to a format specifier and returns the resulting string.
-// It accepts color color identifier (string or int). If color is invalid the function will return
+// It accepts color identifier (string, int, RGB or color.Color). If color is invalid the function will return
 // fmt.Sprintf(format, a).
 func Csprintf(color interface{}, format string, a ...interface{}) string {
 	return colorSprintf(color, format, a...)
@@ -50,94 +47,139 @@ func Csprintf(color interface{}, format string, a ...interface{}) string {
 
 // ClearScreen clears screen
 func ClearScreen() {
-	fmt.Print(Clear)
+	stdTerminal.ClearScreen()
 }
 
 // ClearScreenUp clears screen from current cursor position up
 func ClearScreenUp() {
-	fmt.Print(ClearUp)
+	stdTerminal.ClearScreenUp()
 }
 
 // ClearScreenDown clears screen from current cursor position down
 func ClearScreenDown() {
-	fmt.Print(ClearDown)
+	stdTerminal.ClearScreenDown()
 }
 
 // ClearLine deletes the whole line of text
 func ClearLine() {
-	fmt.Print(ClearL)
+	stdTerminal.ClearLine()
 }
 
 // ClearLineLeft deletes line left of cursor position
 func ClearLineLeft() {
-	fmt.Print(ClearLLeft)
+	stdTerminal.ClearLineLeft()
 }
 
 // ClearLineRight deletes line right of cursor position
 func ClearLineRight() {
-	fmt.Print(ClearLRight)
+	stdTerminal.ClearLineRight()
 }
 
 // GetTermSize returns current terminal size (number of columns and rows).
 // It may fail to get correct values and will return -1, -1 in
 // this case. If you're relying on output to precisely position cursor on screen
-// always check error.
+// always check error. Size of terminal connected to standard output is returned, if standard
+// output is redirected terminal connected to standard input is used.
 func GetTermSize() (columns int, rows int, err error) {
-	return getTermSize()
+	return stdTerminal.Size()
+}
+
+// EnableMouse switches requested mouse reporting modes on in terminal connected to standard output.
+// See Terminal EnableMouse.
+func EnableMouse(mode MouseMode) {
+	stdTerminal.EnableMouse(mode)
+}
+
+// DisableMouse switches all mouse reporting modes off in terminal connected to standard output.
+func DisableMouse() {
+	stdTerminal.DisableMouse()
 }
 
-// MoveCursorTo moves cursor to the specified position in terminal. (0, 0) is upper left.
+// EnableBracketedPaste asks terminal connected to standard output to mark pasted text.
+// See Terminal EnableBracketedPaste.
+func EnableBracketedPaste() {
+	stdTerminal.EnableBracketedPaste()
+}
+
+// DisableBracketedPaste switches bracketed paste off in terminal connected to standard output.
+func DisableBracketedPaste() {
+	stdTerminal.DisableBracketedPaste()
+}
+
+// EnableFocusReporting asks terminal connected to standard output to report focus changes.
+func EnableFocusReporting() {
+	stdTerminal.EnableFocusReporting()
+}
+
+// DisableFocusReporting switches focus reporting off in terminal connected to standard output.
+func DisableFocusReporting() {
+	stdTerminal.DisableFocusReporting()
+}
+
+// PushKeyboardFlags pushes kitty keyboard protocol flags in terminal connected to standard output.
+// See Terminal PushKeyboardFlags and EnableKeyboardFlags.
+func PushKeyboardFlags(flags KeyboardFlags) {
+	stdTerminal.PushKeyboardFlags(flags)
+}
+
+// PopKeyboardFlags restores kitty keyboard protocol flags in terminal connected to standard output.
+func PopKeyboardFlags() {
+	stdTerminal.PopKeyboardFlags()
+}
+
+// MoveCursorTo moves cursor to the specified position in terminal. Columns and rows are counted
+// from 1, so (1, 1) is upper left.
 // Will do nothing if x or y are out of bounds or we can not get size of terminal.
 func MoveCursorTo(column, row int) {
-	moveCursorTo(column, row)
+	stdTerminal.MoveCursorTo(column, row)
 }
 
 // MoveCursorHome moves cursor to the upper left corner of the screen.
-// Essentially the same as MoveCursorTo(0, 0).
+// Essentially the same as MoveCursorTo(1, 1).
 func MoveCursorHome() {
-	moveCursorHome()
+	stdTerminal.MoveCursorHome()
 }
 
 // MoveCursorUp moves cursor up specified number of rows
 func MoveCursorUp(rows int) {
-	moveCursorUp(rows)
+	stdTerminal.MoveCursorUp(rows)
 }
 
 // MoveCursorDown moves cursor down specified number of rows
 func MoveCursorDown(rows int) {
-	moveCursorDown(rows)
+	stdTerminal.MoveCursorDown(rows)
 }
 
 // MoveCursorLeft moves cursor left specified number of columns
 func MoveCursorLeft(columns int) {
-	moveCursorLeft(columns)
+	stdTerminal.MoveCursorLeft(columns)
 }
 
 // MoveCursorRight moves cursor left specified number of columns
 func MoveCursorRight(columns int) {
-	moveCursorRight(columns)
+	stdTerminal.MoveCursorRight(columns)
 }
 
 // MoveCursorToNextRow moves cursor to next row
 func MoveCursorToNextRow() {
-	moveCursorToNextRow()
+	stdTerminal.MoveCursorToNextRow()
 }
 
 // MoveCursorToRow places cursor at the beginning of specified row
 func MoveCursorToRow(row int) {
-	moveCursorToRow(row)
+	stdTerminal.MoveCursorToRow(row)
 }
 
 // SaveCursorPosition saves current cursor position and attributes.
 // Call RestoreCursorPosition() to return
 func SaveCursorPosition() {
-	saveCursorPosition()
+	stdTerminal.SaveCursorPosition()
 }
 
 // RestoreCursorPosition places cursor to original position when
 // SaveCursorPosition was called and restores attributes.
 func RestoreCursorPosition() {
-	restoreCursorPosition()
+	stdTerminal.RestoreCursorPosition()
 }
 
 // PrintAtPositionAndReturn moves cursor in the current terminal to the specified position, prints, and
@@ -145,7 +187,7 @@ func RestoreCursorPosition() {
 // Will print at current cursor position if terminal size is unavailable or supplied column and row
 // are out of range.
 func PrintAtPositionAndReturn(column, row int, a ...interface{}) {
-	printAtPositionAndReturn(column, row, a...)
+	stdTerminal.PrintAtPositionAndReturn(column, row, a...)
 }
 
 // PrintAtPosition moves cursor in the current terminal to the specified position and prints.
@@ -154,5 +196,12 @@ func PrintAtPositionAndReturn(column, row int, a ...interface{}) {
 // Will print at current cursor position if terminal size is unavailable or supplied column and row
 // are out of range.
 func PrintAtPosition(column, row int, a ...interface{}) {
-	printAtPosition(column, row, a...)
+	stdTerminal.PrintAtPosition(column, row, a...)
+}
+
+// PrintCentered prints at specified row of the current terminal so that output is centered horizontally.
+// Escape sequences and wide characters are accounted for when calculating width of output.
+// Will print at current cursor position if terminal size is unavailable.
+func PrintCentered(row int, a ...interface{}) {
+	stdTerminal.PrintCentered(row, a...)
 }
@@ -0,0 +1,27 @@
+package termtools
+
+// EnableFocusReporting asks terminal to report when its window gains or loses focus.
+// KeyReader reports focus changes as FocusEvent.
+func (t *Terminal) EnableFocusReporting() {
+	t.Print(Esc + "[?1004h")
+}
+
+// DisableFocusReporting switches focus reporting off.
+func (t *Terminal) DisableFocusReporting() {
+	t.Print(Esc + "[?1004l")
+}
+
+// FocusEvent reports that terminal window gained (Focused is true) or lost focus.
+type FocusEvent struct {
+	Focused bool
+}
+
+func (FocusEvent) isEvent() {}
+
+// String implements fmt.Stringer. It returns "focus in" or "focus out".
+func (e FocusEvent) String() string {
+	if e.Focused {
+		return "focus in"
+	}
+	return "focus out"
+}
@@ -0,0 +1,136 @@
+package termtools
+
+import (
+	"math"
+	"strings"
+)
+
+// GradientColor returns color at position t in range [0;1] of gradient running through stops.
+// Colors between stops are interpolated linearly (see Blend). If no stops are passed black is returned.
+func GradientColor(t float64, stops ...RGB) RGB {
+	switch len(stops) {
+	case 0:
+		return RGB{}
+	case 1:
+		return stops[0]
+	}
+	pos := clampUnit(t) * float64(len(stops)-1)
+	i := int(math.Floor(pos))
+	if i >= len(stops)-1 {
+		return stops[len(stops)-1]
+	}
+	return Blend(stops[i], stops[i+1], pos-float64(i))
+}
+
+// GradientSprint returns text with foreground color changing from one grapheme to another
+// through stops from left to right. Other settings of printer (modes, background, prefix and suffix) are
+// applied as in Sprint. Multiline text is colored so that columns have the same color.
+// Colors are downsampled to color profile of printer output. Escape sequences in text are removed.
+func (p *Printer) GradientSprint(text string, stops ...RGB) string {
+	return p.gradient(text, layerForeground, linearGradient(stops, false))
+}
+
+// BackgroundGradientSprint works like GradientSprint but changes background color.
+func (p *Printer) BackgroundGradientSprint(text string, stops ...RGB) string {
+	return p.gradient(text, layerBackground, linearGradient(stops, false))
+}
+
+// VerticalGradientSprint returns text with foreground color changing through stops from
+// the first line of text to the last one. See GradientSprint.
+func (p *Printer) VerticalGradientSprint(text string, stops ...RGB) string {
+	return p.gradient(text, layerForeground, linearGradient(stops, true))
+}
+
+// VerticalBackgroundGradientSprint works like VerticalGradientSprint but changes background color.
+func (p *Printer) VerticalBackgroundGradientSprint(text string, stops ...RGB) string {
+	return p.gradient(text, layerBackground, linearGradient(stops, true))
+}
+
+// RainbowSprint returns text with foreground color running through hues of rainbow
+// from red to violet. See GradientSprint.
+func (p *Printer) RainbowSprint(text string) string {
+	return p.gradient(text, layerForeground, func(line, lines, column, width int) RGB {
+		return HSV{H: 300 * position(column, width), S: 1, V: 1}.RGB()
+	})
+}
+
+// colorAtFunc returns color of grapheme at column of line. Arguments lines and width
+// hold number of lines and width of the widest line.
+type colorAtFunc func(line, lines, column, width int) RGB
+
+// linearGradient returns colorAtFunc for horizontal or vertical gradient. It returns nil if there are no stops.
+func linearGradient(stops []RGB, vertical bool) colorAtFunc {
+	if len(stops) == 0 {
+		return nil
+	}
+	if vertical {
+		return func(line, lines, column, width int) RGB {
+			return GradientColor(position(line, lines), stops...)
+		}
+	}
+	return func(line, lines, column, width int) RGB {
+		return GradientColor(position(column, width), stops...)
+	}
+}
+
+// position returns relative position of i-th of n items in range [0;1].
+func position(i, n int) float64 {
+	if n < 2 {
+		return 0
+	}
+	return float64(i) / float64(n-1)
+}
+
+// gradient renders text with color of layer returned by colorAt for each grapheme.
+// If colorAt is nil text is rendered with printer style only.
+func (p *Printer) gradient(text string, layer colorLayer, colorAt colorAtFunc) string {
+	profile := p.colorProfile(p.output())
+	text = StripANSI(text)
+	if colorAt == nil || profile == NoColor {
+		return p.style.render(profile, text)
+	}
+	lines := strings.Split(text, "\n")
+	width := 0
+	for _, line := range lines {
+		if w := VisibleWidth(line); w > width {
+			width = w
+		}
+	}
+	var b strings.Builder
+	active := ""
+	apply := func(codes string) {
+		if codes != active {
+			if active != "" && codes == "" {
+				b.WriteString(Reset)
+			}
+			b.WriteString(codes)
+			active = codes
+		}
+	}
+	s := p.style
+	set := func(c RGB) {
+		if layer == layerBackground {
+			s.bg = colorValue{kind: colorTrue, rgb: c}
+		} else {
+			s.fg = colorValue{kind: colorTrue, rgb: c}
+		}
+		apply(s.codes(profile))
+	}
+	set(colorAt(0, len(lines), 0, width))
+	b.WriteString(s.prefix)
+	for i, line := range lines {
+		if i > 0 {
+			apply("")
+			b.WriteString("\n")
+		}
+		column := 0
+		for _, t := range tokenize(line) {
+			set(colorAt(i, len(lines), column, width))
+			b.WriteString(t.s)
+			column += t.width
+		}
+	}
+	b.WriteString(s.suffix)
+	apply("")
+	return b.String()
+}
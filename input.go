@@ -0,0 +1,352 @@
+package termtools
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultEscapeTimeout is time KeyReader waits for the rest of escape sequence after ESC byte
+// before reporting Escape key.
+const DefaultEscapeTimeout = 50 * time.Millisecond
+
+// DefaultPasteTimeout is time KeyReader waits for the end of pasted text (see EnableBracketedPaste)
+// before reporting text received so far as PasteEvent.
+const DefaultPasteTimeout = 5 * time.Second
+
+// Event is an input event read by KeyReader. It is KeyEvent, MouseEvent,
+// PasteEvent or FocusEvent.
+type Event interface {
+	String() string
+	isEvent()
+}
+
+// Key identifies a key. Keys producing characters are reported as KeyRune.
+type Key int
+
+const (
+	// KeyRune is a key producing character held in Rune field of KeyEvent.
+	KeyRune Key = iota
+	KeyEnter
+	KeyTab
+	KeyBackspace
+	KeyEscape
+	KeyUp
+	KeyDown
+	KeyRight
+	KeyLeft
+	KeyHome
+	KeyEnd
+	KeyPageUp
+	KeyPageDown
+	KeyInsert
+	KeyDelete
+	KeyF1
+	KeyF2
+	KeyF3
+	KeyF4
+	KeyF5
+	KeyF6
+	KeyF7
+	KeyF8
+	KeyF9
+	KeyF10
+	KeyF11
+	KeyF12
+)
+
+var keyNames = map[Key]string{
+	KeyRune: "rune", KeyEnter: "enter", KeyTab: "tab", KeyBackspace: "backspace", KeyEscape: "esc",
+	KeyUp: "up", KeyDown: "down", KeyRight: "right", KeyLeft: "left", KeyHome: "home", KeyEnd: "end",
+	KeyPageUp: "pgup", KeyPageDown: "pgdown", KeyInsert: "insert", KeyDelete: "delete",
+	KeyF1: "f1", KeyF2: "f2", KeyF3: "f3", KeyF4: "f4", KeyF5: "f5", KeyF6: "f6",
+	KeyF7: "f7", KeyF8: "f8", KeyF9: "f9", KeyF10: "f10", KeyF11: "f11", KeyF12: "f12"}
+
+// String implements fmt.Stringer.
+func (k Key) String() string {
+	if name, ok := keyNames[k]; ok {
+		return name
+	}
+	return "Key(unknown)"
+}
+
+// Modifier is a set of modifier keys held while pressing a key.
+type Modifier int
+
+const (
+	ModShift Modifier = 1 << iota
+	ModAlt
+	ModCtrl
+	ModSuper
+	// Modifiers below are reported only with kitty keyboard protocol.
+	ModHyper
+	ModMeta
+	ModCapsLock
+	ModNumLock
+)
+
+var modifierNames = []struct {
+	mod  Modifier
+	name string
+}{{ModCtrl, "ctrl"}, {ModAlt, "alt"}, {ModShift, "shift"}, {ModSuper, "super"},
+	{ModHyper, "hyper"}, {ModMeta, "meta"}, {ModCapsLock, "capslock"}, {ModNumLock, "numlock"}}
+
+// String implements fmt.Stringer. It returns names of modifiers joined with "+", for example "ctrl+alt".
+func (m Modifier) String() string {
+	var names []string
+	for _, mod := range modifierNames {
+		if m&mod.mod != 0 {
+			names = append(names, mod.name)
+		}
+	}
+	return strings.Join(names, "+")
+}
+
+// KeyEvent is a key press. Keys producing characters have Key set to KeyRune and the character
+// in Rune. Ctrl combinations with letters are reported as lowercase letter with ModCtrl, for example
+// Ctrl+C is KeyEvent{Key: KeyRune, Rune: 'c', Mod: ModCtrl}.
+//
+// With kitty keyboard protocol (see PushKeyboardFlags) Rune holds unshifted character, for example
+// Shift+A is reported as 'a' with ModShift. Type, Shifted and BaseRune are set only with the protocol.
+type KeyEvent struct {
+	Key  Key
+	Rune rune
+	Mod  Modifier
+	Type KeyEventType
+	// Shifted is the character key produces with Shift and BaseRune is the key in standard
+	// (US English) keyboard layout. They are reported with KeyboardReportAlternateKeys flag.
+	Shifted, BaseRune rune
+}
+
+func (KeyEvent) isEvent() {}
+
+// String implements fmt.Stringer. It returns modifiers and key name joined with "+",
+// for example "ctrl+c", "alt+enter", "shift+f5" or "a". Repeats and releases are followed
+// by event type like "ctrl+a release".
+func (e KeyEvent) String() string {
+	name := e.Key.String()
+	if e.Key == KeyRune {
+		name = string(e.Rune)
+		if e.Rune == ' ' {
+			name = "space"
+		}
+	}
+	if mods := e.Mod.String(); mods != "" {
+		name = mods + "+" + name
+	}
+	if e.Type != KeyPress {
+		name += " " + e.Type.String()
+	}
+	return name
+}
+
+// KeyReader decodes input from terminal into events. Terminal should be in raw or cbreak mode
+// (see MakeRaw and MakeCbreak), otherwise input is available only after Enter is pressed.
+//
+// Both CSI ("\x1b[A") and SS3 ("\x1bOA") encodings of special keys are recognized. Since Escape key
+// sends the same ESC byte which starts escape sequences, KeyReader waits for the rest of sequence
+// for escape timeout (DefaultEscapeTimeout unless changed with SetEscapeTimeout). ESC followed by
+// another key is reported as that key with ModAlt. Unknown escape sequences are skipped.
+// Sequences of kitty keyboard protocol are decoded as well, see PushKeyboardFlags.
+// Pasted text is not subject to escape timeout: KeyReader waits for the end of pasted text
+// for paste timeout (DefaultPasteTimeout unless changed with SetPasteTimeout) and then
+// reports all input received since the start of pasted text as PasteEvent.
+//
+// Events should be read from single goroutine either with ReadEvent or from channel returned by Events.
+type KeyReader struct {
+	r io.Reader
+	// buf holds bytes read but not decoded yet
+	buf   []byte
+	start sync.Once
+	input chan []byte
+	// newTimer starts timer used to wait for the rest of input, tests replace it
+	newTimer func(time.Duration) (c <-chan time.Time, stop func() bool)
+
+	mu           sync.Mutex // guards fields below
+	timeout      time.Duration
+	pasteTimeout time.Duration
+	err          error
+	// pending holds event read by Events but not delivered because ctx was cancelled
+	pending Event
+}
+
+// NewKeyReader returns KeyReader reading from r which is normally os.Stdin or /dev/tty.
+func NewKeyReader(r io.Reader) *KeyReader {
+	return &KeyReader{r: r, timeout: DefaultEscapeTimeout, pasteTimeout: DefaultPasteTimeout, newTimer: newTimer}
+}
+
+func newTimer(d time.Duration) (<-chan time.Time, func() bool) {
+	timer := time.NewTimer(d)
+	return timer.C, timer.Stop
+}
+
+// SetEscapeTimeout sets time to wait for the rest of escape sequence after ESC byte.
+func (kr *KeyReader) SetEscapeTimeout(d time.Duration) {
+	kr.mu.Lock()
+	kr.timeout = d
+	kr.mu.Unlock()
+}
+
+func (kr *KeyReader) escapeTimeout() time.Duration {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	return kr.timeout
+}
+
+// SetPasteTimeout sets time to wait for the end of pasted text. It limits time keys are
+// taken for pasted text if terminal fails to mark the end of pasted text.
+func (kr *KeyReader) SetPasteTimeout(d time.Duration) {
+	kr.mu.Lock()
+	kr.pasteTimeout = d
+	kr.mu.Unlock()
+}
+
+func (kr *KeyReader) pasteWait() time.Duration {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	return kr.pasteTimeout
+}
+
+// Err returns error which stopped reading from underlying reader (io.EOF at end of input) or nil.
+func (kr *KeyReader) Err() error {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	return kr.err
+}
+
+// readInput reads from underlying reader in separate goroutine so that reads may time out.
+func (kr *KeyReader) readInput() {
+	defer close(kr.input)
+	for {
+		b := make([]byte, 256)
+		n, err := kr.r.Read(b)
+		if n > 0 {
+			kr.input <- b[:n]
+		}
+		if err != nil {
+			kr.mu.Lock()
+			kr.err = err
+			kr.mu.Unlock()
+			return
+		}
+	}
+}
+
+// ReadEvent blocks until the next event is read. It returns error if reading from
+// underlying reader fails, io.EOF is returned at end of input.
+func (kr *KeyReader) ReadEvent() (Event, error) {
+	return kr.readEvent(context.Background())
+}
+
+// Events returns channel delivering events until ctx is cancelled or reading from
+// underlying reader fails (see Err). The channel is closed then. Note that reading from
+// underlying reader can not be interrupted: after ctx is cancelled KeyReader keeps
+// waiting for input in background and the next input is available to subsequent reads.
+// Event which was read but could not be delivered before ctx was cancelled is not lost
+// either: it is returned by the next read after the channel is closed.
+func (kr *KeyReader) Events(ctx context.Context) <-chan Event {
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		for {
+			ev, err := kr.readEvent(ctx)
+			if err != nil {
+				return
+			}
+			select {
+			case events <- ev:
+			case <-ctx.Done():
+				kr.mu.Lock()
+				kr.pending = ev
+				kr.mu.Unlock()
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// readEvent reads the next event until ctx is cancelled.
+func (kr *KeyReader) readEvent(ctx context.Context) (Event, error) {
+	kr.start.Do(func() {
+		kr.input = make(chan []byte)
+		go kr.readInput()
+	})
+	kr.mu.Lock()
+	ev := kr.pending
+	kr.pending = nil
+	kr.mu.Unlock()
+	if ev != nil {
+		return ev, nil
+	}
+	var (
+		timeout    <-chan time.Time
+		stop       func() bool
+		pasteTimer bool
+	)
+	setTimer := func(d time.Duration, paste bool) {
+		if stop != nil {
+			stop()
+		}
+		timeout, stop = kr.newTimer(d)
+		pasteTimer = paste
+	}
+	defer func() {
+		if stop != nil {
+			stop()
+		}
+	}()
+	for {
+		if len(kr.buf) > 0 {
+			if ev, ok := kr.decode(false); ok {
+				return ev, nil
+			}
+			switch pasting := isPasting(kr.buf); {
+			case pasting && !pasteTimer:
+				// wait for the end of pasted text, escape timer is not used even if
+				// it was set before the start of pasted text was recognized
+				setTimer(kr.pasteWait(), true)
+			case !pasting && timeout == nil:
+				// wait for the rest of incomplete sequence
+				setTimer(kr.escapeTimeout(), false)
+			}
+		}
+		select {
+		case b, ok := <-kr.input:
+			if !ok {
+				for len(kr.buf) > 0 {
+					if ev, ok := kr.decode(true); ok {
+						return ev, nil
+					}
+				}
+				return nil, kr.Err()
+			}
+			kr.buf = append(kr.buf, b...)
+		case <-timeout:
+			timeout, pasteTimer = nil, false
+			if ev, ok := kr.decode(true); ok {
+				return ev, nil
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// decode decodes events from buffer until an event is found or buffer holds incomplete sequence.
+// If final is true incomplete sequence is decoded as is.
+func (kr *KeyReader) decode(final bool) (Event, bool) {
+	for len(kr.buf) > 0 {
+		ev, n := decodeEvent(kr.buf, final)
+		if n == 0 {
+			return nil, false
+		}
+		kr.buf = kr.buf[n:]
+		if ev != nil {
+			return ev, true
+		}
+	}
+	return nil, false
+}
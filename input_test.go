@@ -0,0 +1,347 @@
+package termtools
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_DecodeKeys(t *testing.T) {
+	cases := []struct {
+		in   string
+		want KeyEvent
+		n    int
+	}{
+		{"a", KeyEvent{Key: KeyRune, Rune: 'a'}, 1},
+		{"ж", KeyEvent{Key: KeyRune, Rune: 'ж'}, 2},
+		{"\r", KeyEvent{Key: KeyEnter}, 1},
+		{"\t", KeyEvent{Key: KeyTab}, 1},
+		{"\x7f", KeyEvent{Key: KeyBackspace}, 1},
+		{"\x03", KeyEvent{Key: KeyRune, Rune: 'c', Mod: ModCtrl}, 1},
+		{"\x1c", KeyEvent{Key: KeyRune, Rune: '\\', Mod: ModCtrl}, 1},
+		{"\x1bx", KeyEvent{Key: KeyRune, Rune: 'x', Mod: ModAlt}, 2},
+		{"\x1b\x01", KeyEvent{Key: KeyRune, Rune: 'a', Mod: ModAlt | ModCtrl}, 2},
+		{"\x1b[A", KeyEvent{Key: KeyUp}, 3},
+		{"\x1bOB", KeyEvent{Key: KeyDown}, 3},
+		{"\x1b[1;5C", KeyEvent{Key: KeyRight, Mod: ModCtrl}, 6},
+		{"\x1b[1;3D", KeyEvent{Key: KeyLeft, Mod: ModAlt}, 6},
+		{"\x1b[H", KeyEvent{Key: KeyHome}, 3},
+		{"\x1bOF", KeyEvent{Key: KeyEnd}, 3},
+		{"\x1b[5~", KeyEvent{Key: KeyPageUp}, 4},
+		{"\x1b[6;2~", KeyEvent{Key: KeyPageDown, Mod: ModShift}, 6},
+		{"\x1b[3~", KeyEvent{Key: KeyDelete}, 4},
+		{"\x1bOP", KeyEvent{Key: KeyF1}, 3},
+		{"\x1b[1;2S", KeyEvent{Key: KeyF4, Mod: ModShift}, 6},
+		{"\x1b[15~", KeyEvent{Key: KeyF5}, 5},
+		{"\x1b[24;5~", KeyEvent{Key: KeyF12, Mod: ModCtrl}, 7},
+		{"\x1b[[B", KeyEvent{Key: KeyF2}, 4},
+		{"\x1b[Z", KeyEvent{Key: KeyTab, Mod: ModShift}, 3},
+		{"\x1b\x1b[A", KeyEvent{Key: KeyUp, Mod: ModAlt}, 4},
+	}
+	for _, c := range cases {
+		ev, n := decodeEvent([]byte(c.in), false)
+		if ev != c.want || n != c.n {
+			t.Errorf("decodeEvent(%q) = %v, %d; want %v, %d", c.in, ev, n, c.want, c.n)
+		}
+	}
+	for _, in := range []string{"\x1b", "\x1b[", "\x1b[1;5", "\x1bO", "\xd0"} {
+		if ev, n := decodeEvent([]byte(in), false); n != 0 {
+			t.Errorf("decodeEvent(%q) of incomplete sequence = %v, %d", in, ev, n)
+		}
+	}
+	final := []struct {
+		in   string
+		want KeyEvent
+		n    int
+	}{
+		{"\x1b", KeyEvent{Key: KeyEscape}, 1},
+		{"\x1b[", KeyEvent{Key: KeyRune, Rune: '[', Mod: ModAlt}, 2},
+		{"\x1bO", KeyEvent{Key: KeyRune, Rune: 'O', Mod: ModAlt}, 2},
+	}
+	for _, c := range final {
+		if ev, n := decodeEvent([]byte(c.in), true); ev != c.want || n != c.n {
+			t.Errorf("decodeEvent(%q, final) = %v, %d; want %v, %d", c.in, ev, n, c.want, c.n)
+		}
+	}
+	if ev, n := decodeEvent([]byte("\x1b[99~x"), false); ev != nil || n != 5 {
+		t.Errorf("unknown sequence: %v, %d", ev, n)
+	}
+	names := map[string]KeyEvent{
+		"ctrl+c": {Key: KeyRune, Rune: 'c', Mod: ModCtrl}, "ctrl+alt+shift+f5": {Key: KeyF5, Mod: ModShift | ModAlt | ModCtrl},
+		"space": {Key: KeyRune, Rune: ' '}, "esc": {Key: KeyEscape}}
+	for want, ev := range names {
+		if ev.String() != want {
+			t.Errorf("String() = %q want %q", ev.String(), want)
+		}
+	}
+}
+
+func Test_DecodeMouse(t *testing.T) {
+	cases := []struct {
+		in   string
+		want MouseEvent
+		n    int
+	}{
+		{"\x1b[<0;10;5M", MouseEvent{X: 10, Y: 5, Button: MouseLeft}, 10},
+		{"\x1b[<2;300;200m", MouseEvent{X: 300, Y: 200, Button: MouseRight, Action: MouseRelease}, 13},
+		{"\x1b[<16;1;1M", MouseEvent{X: 1, Y: 1, Button: MouseLeft, Modifiers: ModCtrl}, 10},
+		{"\x1b[<32;3;4M", MouseEvent{X: 3, Y: 4, Button: MouseLeft, Action: MouseMotion}, 10},
+		{"\x1b[<35;3;4M", MouseEvent{X: 3, Y: 4, Button: MouseNoButton, Action: MouseMotion}, 10},
+		{"\x1b[<65;7;8M", MouseEvent{X: 7, Y: 8, Button: MouseWheelDown}, 10},
+		{"\x1b[<128;2;2M", MouseEvent{X: 2, Y: 2, Button: MouseBackward}, 11},
+		{"\x1b[M !!", MouseEvent{X: 1, Y: 1, Button: MouseLeft}, 6},
+		{"\x1b[M#*%", MouseEvent{X: 10, Y: 5, Button: MouseNoButton, Action: MouseRelease}, 6},
+		{"\x1b[M5!!", MouseEvent{X: 1, Y: 1, Button: MouseMiddle, Modifiers: ModCtrl | ModShift}, 6},
+	}
+	for _, c := range cases {
+		ev, n := decodeEvent([]byte(c.in), false)
+		if ev != c.want || n != c.n {
+			t.Errorf("decodeEvent(%q) = %v, %d; want %v, %d", c.in, ev, n, c.want, c.n)
+		}
+	}
+	for _, in := range []string{"\x1b[<0;10", "\x1b[M", "\x1b[M !"} {
+		if ev, n := decodeEvent([]byte(in), false); n != 0 {
+			t.Errorf("decodeEvent(%q) of incomplete sequence = %v, %d", in, ev, n)
+		}
+	}
+	// coordinates of mouse report are accepted by MoveCursorTo as is
+	cells := []struct {
+		in, want string
+	}{
+		{"\x1b[<0;1;1M", "\x1b[1;1H"},
+		{"\x1b[M !!", "\x1b[1;1H"},
+		{"\x1b[<0;10;5M", "\x1b[5;10H"},
+		{"\x1b[M *%", "\x1b[5;10H"},
+	}
+	for _, c := range cells {
+		ev, _ := decodeEvent([]byte(c.in), false)
+		mouse := ev.(MouseEvent)
+		var b strings.Builder
+		NewTerminal(&b, -1).MoveCursorTo(mouse.X, mouse.Y)
+		if b.String() != c.want {
+			t.Errorf("MoveCursorTo cell reported by %q wrote %q, want %q", c.in, b.String(), c.want)
+		}
+	}
+	if s := (MouseEvent{X: 10, Y: 5, Button: MouseLeft, Modifiers: ModCtrl}).String(); s != "ctrl+left press 10,5" {
+		t.Errorf("MouseEvent String() = %q", s)
+	}
+	var b strings.Builder
+	term := NewTerminal(&b, -1)
+	term.EnableMouse(MouseButtonEvent | MouseSGR)
+	term.DisableMouse()
+	if want := "\x1b[?1002h\x1b[?1006h\x1b[?1006l\x1b[?1003l\x1b[?1002l\x1b[?1000l\x1b[?9l"; b.String() != want {
+		t.Errorf("EnableMouse and DisableMouse wrote %q, want %q", b.String(), want)
+	}
+}
+
+func Test_DecodePasteAndFocus(t *testing.T) {
+	cases := []struct {
+		in    string
+		final bool
+		want  Event
+		n     int
+	}{
+		{"\x1b[200~one\r\ntwo\rthree\x1b[201~x", false, PasteEvent{Text: "one\ntwo\nthree"}, 26},
+		{"\x1b[200~\x1b[A\x1b[201~", false, PasteEvent{Text: "\x1b[A"}, 15},
+		{"\x1b[200~abc", false, nil, 0},
+		{"\x1b[200~abc", true, PasteEvent{Text: "abc"}, 9},
+		{"\x1b[I", false, FocusEvent{Focused: true}, 3},
+		{"\x1b[O", false, FocusEvent{Focused: false}, 3},
+	}
+	for _, c := range cases {
+		if ev, n := decodeEvent([]byte(c.in), c.final); ev != c.want || n != c.n {
+			t.Errorf("decodeEvent(%q, %v) = %v, %d; want %v, %d", c.in, c.final, ev, n, c.want, c.n)
+		}
+	}
+	if s := (PasteEvent{Text: "a\nb"}).String(); s != `paste "a\nb"` {
+		t.Errorf("PasteEvent String() = %q", s)
+	}
+	var b strings.Builder
+	term := NewTerminal(&b, -1)
+	term.EnableBracketedPaste()
+	term.EnableFocusReporting()
+	term.DisableFocusReporting()
+	term.DisableBracketedPaste()
+	if want := "\x1b[?2004h\x1b[?1004h\x1b[?1004l\x1b[?2004l"; b.String() != want {
+		t.Errorf("paste and focus modes wrote %q, want %q", b.String(), want)
+	}
+
+	// pasted text is not cut by escape timeout and is flushed by paste timeout if end marker is lost
+	r, w := io.Pipe()
+	kr := NewKeyReader(r)
+	timers := fakeTimers(kr)
+	events := kr.Events(context.Background())
+	w.Write([]byte("\x1b[20"))
+	escape := nextTimer(t, timers, DefaultEscapeTimeout)
+	w.Write([]byte("0~line\r"))
+	nextTimer(t, timers, DefaultPasteTimeout)
+	escape <- time.Now()
+	w.Write([]byte("\x1b[201~\x1b[I"))
+	for _, want := range []Event{PasteEvent{Text: "line\n"}, FocusEvent{Focused: true}} {
+		if ev := <-events; ev != want {
+			t.Fatalf("Events: got %v, want %v", ev, want)
+		}
+	}
+	w.Write([]byte("\x1b[200~abc"))
+	nextTimer(t, timers, DefaultPasteTimeout) <- time.Now()
+	if ev, want := <-events, (PasteEvent{Text: "abc"}); ev != want {
+		t.Fatalf("Events: got %v, want %v", ev, want)
+	}
+	w.Close()
+	if ev, ok := <-events; ok {
+		t.Errorf("Events: got %v at end of input", ev)
+	}
+}
+
+// fakeTimers makes kr use timers which fire only when test sends to them.
+// Timers started by kr are sent to returned channel.
+func fakeTimers(kr *KeyReader) <-chan fakeTimer {
+	timers := make(chan fakeTimer, 16)
+	kr.newTimer = func(d time.Duration) (<-chan time.Time, func() bool) {
+		timer := fakeTimer{d: d, c: make(chan time.Time, 1)}
+		timers <- timer
+		return timer.c, func() bool { return true }
+	}
+	return timers
+}
+
+type fakeTimer struct {
+	d time.Duration
+	c chan time.Time
+}
+
+// nextTimer waits for kr to start timer for duration d and returns its channel.
+func nextTimer(t *testing.T, timers <-chan fakeTimer, d time.Duration) chan<- time.Time {
+	t.Helper()
+	select {
+	case timer := <-timers:
+		if timer.d != d {
+			t.Fatalf("KeyReader started timer for %v, want %v", timer.d, d)
+		}
+		return timer.c
+	case <-time.After(5 * time.Second):
+		t.Fatalf("KeyReader did not start timer for %v", d)
+		return nil
+	}
+}
+
+func Test_DecodeKitty(t *testing.T) {
+	cases := []struct {
+		in   string
+		want Event
+	}{
+		{"\x1b[105;5u", KeyEvent{Key: KeyRune, Rune: 'i', Mod: ModCtrl}},
+		{"\x1b[9u", KeyEvent{Key: KeyTab}},
+		{"\x1b[27u", KeyEvent{Key: KeyEscape}},
+		{"\x1b[13;3u", KeyEvent{Key: KeyEnter, Mod: ModAlt}},
+		{"\x1b[97;2u", KeyEvent{Key: KeyRune, Rune: 'a', Mod: ModShift}},
+		{"\x1b[97:65;2u", KeyEvent{Key: KeyRune, Rune: 'a', Shifted: 'A', Mod: ModShift}},
+		{"\x1b[1092::97;5u", KeyEvent{Key: KeyRune, Rune: 'ф', BaseRune: 'a', Mod: ModCtrl}},
+		{"\x1b[97;1:2u", KeyEvent{Key: KeyRune, Rune: 'a', Type: KeyRepeat}},
+		{"\x1b[97;5:3u", KeyEvent{Key: KeyRune, Rune: 'a', Mod: ModCtrl, Type: KeyRelease}},
+		{"\x1b[97;17u", KeyEvent{Key: KeyRune, Rune: 'a', Mod: ModHyper}},
+		{"\x1b[97;161u", KeyEvent{Key: KeyRune, Rune: 'a', Mod: ModNumLock | ModMeta}},
+		{"\x1b[97;65;65u", KeyEvent{Key: KeyRune, Rune: 'a', Mod: ModCapsLock}},
+		{"\x1b[57400u", KeyEvent{Key: KeyRune, Rune: '1'}},
+		{"\x1b[57419;2u", KeyEvent{Key: KeyUp, Mod: ModShift}},
+		{"\x1b[1;1:3A", KeyEvent{Key: KeyUp, Type: KeyRelease}},
+		{"\x1b[3;5:2~", KeyEvent{Key: KeyDelete, Mod: ModCtrl, Type: KeyRepeat}},
+		{"\x1b[57441;2u", nil},
+		{"\x1b[?5u", nil},
+	}
+	for _, c := range cases {
+		if ev, n := decodeEvent([]byte(c.in), false); ev != c.want || n != len(c.in) {
+			t.Errorf("decodeEvent(%q) = %v, %d; want %v, %d", c.in, ev, n, c.want, len(c.in))
+		}
+	}
+	names := map[string]KeyEvent{
+		"ctrl+a release":          {Key: KeyRune, Rune: 'a', Mod: ModCtrl, Type: KeyRelease},
+		"super+hyper+x":           {Key: KeyRune, Rune: 'x', Mod: ModSuper | ModHyper},
+		"meta+capslock+f1 repeat": {Key: KeyF1, Mod: ModMeta | ModCapsLock, Type: KeyRepeat},
+	}
+	for want, ev := range names {
+		if ev.String() != want {
+			t.Errorf("KeyEvent String() = %q, want %q", ev.String(), want)
+		}
+	}
+	if flags, err := parseKeyboardFlags("\x1b[?13u\x1b[?62;22c"); err != nil || flags != KeyboardDisambiguate|KeyboardReportAlternateKeys|KeyboardReportAllKeys {
+		t.Errorf("parseKeyboardFlags() = %v, %v", flags, err)
+	}
+	if _, err := parseKeyboardFlags("\x1b[?62;22c"); err != ErrKeyboardProtocolUnsupported {
+		t.Errorf("parseKeyboardFlags() without reply returned %v", err)
+	}
+	var b strings.Builder
+	term := NewTerminal(&b, -1)
+	term.PushKeyboardFlags(KeyboardDisambiguate | KeyboardReportEvents)
+	term.PopKeyboardFlags()
+	if want := "\x1b[>3u\x1b[<u"; b.String() != want {
+		t.Errorf("PushKeyboardFlags and PopKeyboardFlags wrote %q, want %q", b.String(), want)
+	}
+}
+
+func Test_KeyReader(t *testing.T) {
+	r, w := io.Pipe()
+	kr := NewKeyReader(r)
+	kr.SetEscapeTimeout(20 * time.Millisecond)
+	go func() {
+		w.Write([]byte("a\x1b[1;5"))
+		time.Sleep(5 * time.Millisecond)
+		w.Write([]byte("A\x1b"))
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte("\x1bb"))
+		w.Close()
+	}()
+	want := []string{"a", "ctrl+up", "esc", "alt+b"}
+	for _, name := range want {
+		ev, err := kr.ReadEvent()
+		if err != nil || ev.String() != name {
+			t.Fatalf("ReadEvent() = %v, %v; want %s", ev, err, name)
+		}
+	}
+	if ev, err := kr.ReadEvent(); err != io.EOF {
+		t.Errorf("ReadEvent() at end of input = %v, %v", ev, err)
+	}
+
+	r, w = io.Pipe()
+	kr = NewKeyReader(r)
+	ctx, cancel := context.WithCancel(context.Background())
+	events := kr.Events(ctx)
+	go w.Write([]byte("x"))
+	if ev := <-events; ev.String() != "x" {
+		t.Errorf("Events: got %v", ev)
+	}
+	cancel()
+	if _, ok := <-events; ok {
+		t.Error("Events channel is not closed after cancel")
+	}
+
+	// event read but not delivered before cancel is returned by the next read
+	r, w = io.Pipe()
+	kr = NewKeyReader(r)
+	ctx, cancel = context.WithCancel(context.Background())
+	events = kr.Events(ctx)
+	go func() {
+		w.Write([]byte("ab"))
+		w.Close()
+	}()
+	if ev := <-events; ev.String() != "a" {
+		t.Errorf("Events: got %v", ev)
+	}
+	cancel()
+	var got []string
+	for ev := range events {
+		got = append(got, ev.String())
+	}
+	for {
+		ev, err := kr.ReadEvent()
+		if err != nil {
+			break
+		}
+		got = append(got, ev.String())
+	}
+	if len(got) != 1 || got[0] != "b" {
+		t.Errorf("events after cancel: got %q, want [b]", got)
+	}
+}
@@ -0,0 +1,139 @@
+package termtools
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// ErrKeyboardProtocolUnsupported is returned by QueryKeyboardFlags when terminal does not support
+// kitty keyboard protocol.
+var ErrKeyboardProtocolUnsupported = errors.New("error: terminal does not support kitty keyboard protocol")
+
+// KeyboardFlags is a set of kitty keyboard protocol enhancements which may be combined with "|".
+// See https://sw.kovidgoyal.net/kitty/keyboard-protocol/ for details.
+type KeyboardFlags int
+
+const (
+	// KeyboardDisambiguate makes terminal send unambiguous sequences for keys like Escape,
+	// Ctrl+I (as opposed to Tab) or Alt combinations.
+	KeyboardDisambiguate KeyboardFlags = 1 << iota
+	// KeyboardReportEvents makes terminal report key repeats and releases.
+	KeyboardReportEvents
+	// KeyboardReportAlternateKeys makes terminal report shifted key and key of base keyboard layout.
+	KeyboardReportAlternateKeys
+	// KeyboardReportAllKeys makes terminal send escape sequences for all keys including Enter,
+	// Tab, Backspace and keys producing characters.
+	KeyboardReportAllKeys
+	// KeyboardReportText makes terminal send text produced by key. KeyReader ignores the text.
+	KeyboardReportText
+)
+
+// PushKeyboardFlags pushes flags onto the terminal's stack of kitty keyboard protocol flags. Terminals
+// which do not support the protocol ignore it. Restore previous flags with PopKeyboardFlags before exit.
+func (t *Terminal) PushKeyboardFlags(flags KeyboardFlags) {
+	t.Printf(Esc+"[>%du", flags)
+}
+
+// PopKeyboardFlags restores kitty keyboard protocol flags which were active before the last PushKeyboardFlags.
+func (t *Terminal) PopKeyboardFlags() {
+	t.Print(Esc + "[<u")
+}
+
+// keyboardFlagsReply matches reply to kitty keyboard protocol query.
+var keyboardFlagsReply = regexp.MustCompile(`\x1b\[\?(\d+)u`)
+
+// QueryKeyboardFlags asks terminal for active kitty keyboard protocol flags. If terminal does not
+// support the protocol or does not reply within timeout ErrKeyboardProtocolUnsupported is returned.
+// The query is written to controlling terminal (/dev/tty) so it should not be called while
+// KeyReader reads from the terminal.
+func QueryKeyboardFlags(timeout time.Duration) (KeyboardFlags, error) {
+	reply, ok := queryTerminal(Esc+"[?u", timeout)
+	if !ok {
+		return 0, ErrKeyboardProtocolUnsupported
+	}
+	return parseKeyboardFlags(reply)
+}
+
+func parseKeyboardFlags(reply string) (KeyboardFlags, error) {
+	m := keyboardFlagsReply.FindStringSubmatch(reply)
+	if m == nil {
+		return 0, ErrKeyboardProtocolUnsupported
+	}
+	flags, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, ErrKeyboardProtocolUnsupported
+	}
+	return KeyboardFlags(flags), nil
+}
+
+// EnableKeyboardFlags pushes flags in terminal connected to standard output if it supports kitty
+// keyboard protocol (see QueryKeyboardFlags) and returns function which pops them. If terminal does
+// not support the protocol nothing is written and KeyReader keeps decoding legacy key sequences,
+// returned function does nothing then.
+func EnableKeyboardFlags(flags KeyboardFlags, timeout time.Duration) (disable func()) {
+	if _, err := QueryKeyboardFlags(timeout); err != nil {
+		return func() {}
+	}
+	stdTerminal.PushKeyboardFlags(flags)
+	return stdTerminal.PopKeyboardFlags
+}
+
+// KeyEventType tells whether key was pressed, repeated or released. Repeats and releases are
+// reported only with KeyboardReportEvents flag of kitty keyboard protocol.
+type KeyEventType int
+
+const (
+	KeyPress KeyEventType = iota
+	KeyRepeat
+	KeyRelease
+)
+
+var keyEventTypeNames = map[KeyEventType]string{KeyPress: "press", KeyRepeat: "repeat", KeyRelease: "release"}
+
+// String implements fmt.Stringer.
+func (t KeyEventType) String() string {
+	if name, ok := keyEventTypeNames[t]; ok {
+		return name
+	}
+	return "KeyEventType(unknown)"
+}
+
+// kittyKeys maps key codes of CSI u sequences which are not characters to keys.
+var kittyKeys = map[int]Key{
+	9: KeyTab, 13: KeyEnter, 27: KeyEscape, 127: KeyBackspace,
+	// keypad keys are reported as their main keyboard counterparts
+	57414: KeyEnter, 57417: KeyLeft, 57418: KeyRight, 57419: KeyUp, 57420: KeyDown, 57421: KeyPageUp,
+	57422: KeyPageDown, 57423: KeyHome, 57424: KeyEnd, 57425: KeyInsert, 57426: KeyDelete}
+
+// kittyKeypadRunes maps key codes of keypad keys producing characters to the characters.
+var kittyKeypadRunes = map[int]rune{
+	57399: '0', 57400: '1', 57401: '2', 57402: '3', 57403: '4', 57404: '5', 57405: '6', 57406: '7',
+	57407: '8', 57408: '9', 57409: '.', 57410: '/', 57411: '*', 57412: '-', 57413: '+', 57415: '=', 57416: ','}
+
+// decodeKittyKey decodes parameters of CSI u sequence "code:shifted:base;modifiers:event;text".
+// Keys which have no counterpart in Key (modifier keys, media keys and alike) are reported as nil event.
+func decodeKittyKey(params csiParams) Event {
+	code := params.sub(0, 0, -1)
+	ev := KeyEvent{Mod: params.modifier(1), Type: params.eventType(1)}
+	if key, ok := kittyKeys[code]; ok {
+		ev.Key = key
+	} else if r, ok := kittyKeypadRunes[code]; ok {
+		ev.Key, ev.Rune = KeyRune, r
+	} else if code >= ' ' && (code < 0xe000 || code > 0xf8ff) && code <= 0x10ffff {
+		// characters outside of private use area which kitty uses for functional keys
+		ev.Key, ev.Rune = KeyRune, rune(code)
+	} else {
+		return nil
+	}
+	if ev.Key == KeyRune {
+		if shifted := params.sub(0, 1, 0); shifted > 0 {
+			ev.Shifted = rune(shifted)
+		}
+		if base := params.sub(0, 2, 0); base > 0 {
+			ev.BaseRune = rune(base)
+		}
+	}
+	return ev
+}
@@ -0,0 +1,187 @@
+package termtools
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrUnterminatedTag is returned when markup tag has no closing bracket.
+	ErrUnterminatedTag = errors.New("error: tag is not terminated with \"]\"")
+	// ErrEmptyTag is returned when markup contains empty tag "[]".
+	ErrEmptyTag = errors.New("error: empty tag")
+	// ErrUnexpectedClosingTag is returned when closing tag does not match the innermost open tag.
+	ErrUnexpectedClosingTag = errors.New("error: closing tag does not match open tag")
+)
+
+// MarkupError describes failure to parse markup tag.
+type MarkupError struct {
+	// Pos is the position (in bytes) of the tag in markup string
+	Pos int
+	// Tag holds tag contents without brackets
+	Tag string
+	Err error
+}
+
+func (e *MarkupError) Error() string {
+	return fmt.Sprintf("%v in markup tag [%s] at position %d", e.Err, e.Tag, e.Pos)
+}
+
+// Unwrap returns underlying error.
+func (e *MarkupError) Unwrap() error {
+	return e.Err
+}
+
+// Markup renders format string with inline markup and then formats it with arguments
+// as fmt.Sprintf does. Markup tags are enclosed in square brackets and hold style
+// specification as accepted by ParseStyle: space separated attribute names, color names,
+// hex values and color IDs. Color following word "on" sets background. Tag "[/]" closes the innermost open tag,
+// tag "[/spec]" does the same but requires spec to match the opening tag. Tags may be nested:
+// when inner tag is closed style of the outer tags is restored. Tags which are left open
+// are closed at the end of string.
+//
+// Brackets holding only numbers, like "[0]" or "[1 2]", are not tags and are output as is, use
+// "fg:208" to set color by ID. To output other text in brackets literally double the opening
+// bracket: "[[red]" is output as "[red]".
+//
+//	s, err := termtools.Markup("[bold red]error:[/] file [underline]%s[/] not found", name)
+//
+// If tag can not be parsed Markup returns empty string and *MarkupError.
+// Colors are downsampled to profile detected for standard output.
+// Arguments are substituted after markup is processed, so brackets in arguments are output as is.
+func Markup(format string, a ...interface{}) (string, error) {
+	return markup(format, DetectColorProfile(), nil, a...)
+}
+
+// Markup renders markup as package level Markup function does. Words in tags may also refer to names
+// of printers added to the PrintSuite. Style of named printer (including prefix and suffix)
+// is applied to text enclosed in tag. Colors and attributes given explicitly in the tag
+// take precedence over those of named printers.
+//
+//	s, err := suite.Markup("[error]failed:[/] %v", err)
+func (suite *PrintSuite) Markup(format string, a ...interface{}) (string, error) {
+	suite.ensureMapExists()
+	lookup := func(name string) (Style, bool) {
+		if p, ok := suite.available[name]; ok {
+			return p.style, true
+		}
+		return Style{}, false
+	}
+	return markup(format, suite.colorProfile(suite.output()), lookup, a...)
+}
+
+func markup(format string, profile ColorProfile, lookup func(string) (Style, bool), a ...interface{}) (string, error) {
+	var b strings.Builder
+	if err := renderMarkup(&b, format, profile, lookup); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(b.String(), a...), nil
+}
+
+// markupFrame is a style opened by markup tag.
+type markupFrame struct {
+	tag   string
+	own   Style // style specified in tag
+	style Style // own style combined with outer styles
+	// styled reports whether tag outputs escape codes, closing unstyled tag does not reset style
+	styled bool
+}
+
+// renderMarkup writes s with tags replaced by escape codes, prefixes and suffixes of styles.
+// Output is used as format string, so "%" in prefixes and suffixes is escaped.
+func renderMarkup(w io.StringWriter, s string, profile ColorProfile, lookup func(string) (Style, bool)) error {
+	var stack []markupFrame
+	current := func() Style {
+		if len(stack) == 0 {
+			return Style{}
+		}
+		return stack[len(stack)-1].style
+	}
+	for pos := 0; pos < len(s); {
+		i := strings.IndexByte(s[pos:], '[')
+		if i < 0 {
+			w.WriteString(s[pos:])
+			break
+		}
+		w.WriteString(s[pos : pos+i])
+		pos += i
+		if strings.HasPrefix(s[pos:], "[[") {
+			w.WriteString("[")
+			pos += 2
+			continue
+		}
+		end := strings.IndexByte(s[pos:], ']')
+		if end < 0 {
+			return &MarkupError{Pos: pos, Tag: s[pos+1:], Err: ErrUnterminatedTag}
+		}
+		tag := s[pos+1 : pos+end]
+		spec := strings.TrimSpace(tag)
+		if isNumericTag(spec) {
+			w.WriteString(s[pos : pos+end+1])
+			pos += end + 1
+			continue
+		}
+		switch {
+		case spec == "":
+			return &MarkupError{Pos: pos, Tag: tag, Err: ErrEmptyTag}
+		case spec[0] == '/':
+			closing := strings.TrimSpace(spec[1:])
+			if len(stack) == 0 || (closing != "" && closing != stack[len(stack)-1].tag) {
+				return &MarkupError{Pos: pos, Tag: tag, Err: ErrUnexpectedClosingTag}
+			}
+			closed := stack[len(stack)-1]
+			w.WriteString(escapePercent(closed.own.suffix))
+			stack = stack[:len(stack)-1]
+			if closed.styled {
+				w.WriteString(resetTo(current(), profile))
+			}
+		default:
+			conf, named, err := parseStyleSpec(spec, lookup)
+			if err != nil {
+				return &MarkupError{Pos: pos, Tag: tag, Err: err}
+			}
+			own := conf.Style().Inherit(named)
+			frame := markupFrame{tag: spec, own: own, style: own.Inherit(current()), styled: own.codes(profile) != ""}
+			stack = append(stack, frame)
+			w.WriteString(frame.style.codes(profile))
+			w.WriteString(escapePercent(own.prefix))
+		}
+		pos += end + 1
+	}
+	styled := false
+	for i := len(stack) - 1; i >= 0; i-- {
+		w.WriteString(escapePercent(stack[i].own.suffix))
+		styled = styled || stack[i].styled
+	}
+	if styled {
+		w.WriteString(resetTo(Style{}, profile))
+	}
+	return nil
+}
+
+// isNumericTag reports whether tag consists of numbers only, such tags are output as is.
+func isNumericTag(spec string) bool {
+	words := strings.Fields(spec)
+	for _, word := range words {
+		if _, err := strconv.Atoi(word); err != nil {
+			return false
+		}
+	}
+	return len(words) > 0
+}
+
+// resetTo returns escape sequences which reset output style to s.
+func resetTo(s Style, profile ColorProfile) string {
+	if profile == NoColor {
+		return ""
+	}
+	return Reset + s.codes(profile)
+}
+
+// escapePercent escapes s for use in format string of fmt.Sprintf.
+func escapePercent(s string) string {
+	return strings.Replace(s, "%", "%%", -1)
+}
@@ -0,0 +1,134 @@
+package termtools
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_Markup(t *testing.T) {
+	cases := []struct {
+		in, want string
+	}{
+		{"[bold red]error:[/] file", Red + Bold + "error:" + Reset + " file"},
+		{"[red]a [underline]b[/underline] c[/]", Red + "a " + Red + Underline + "b" + Reset + Red + " c" + Reset},
+		{"[[not a tag] [blue on yellow]x", "[not a tag] " + Blue + BYellow + "x" + Reset},
+		{"[ITALIC 9]x[/]", BrightRed + Italic + "x" + Reset},
+		{"plain ] text", "plain ] text"},
+		{"item[0] [1 2]", "item[0] [1 2]"},
+		{"[red]a[0]b[/]", Red + "a[0]b" + Reset},
+		{"[fg:9]x", BrightRed + "x" + Reset},
+	}
+	for _, c := range cases {
+		var b strings.Builder
+		if err := renderMarkup(&b, c.in, ANSI16, nil); err != nil {
+			t.Errorf("%q: %v", c.in, err)
+			continue
+		}
+		if b.String() != c.want {
+			t.Errorf("%q: got %q want %q", c.in, b.String(), c.want)
+		}
+	}
+	var b strings.Builder
+	renderMarkup(&b, "[bold red]x[/] [[y]", NoColor, nil)
+	if b.String() != "x [y]" {
+		t.Errorf("NoColor: got %q", b.String())
+	}
+
+	errs := []struct {
+		in  string
+		err error
+	}{
+		{"[bold purple]x", ErrUnknownStyle},
+		{"[red]x[/blue]", ErrUnexpectedClosingTag},
+		{"x[/]", ErrUnexpectedClosingTag},
+		{"[red x", ErrUnterminatedTag},
+		{"[]", ErrEmptyTag},
+		{"[on]", ErrUnknownStyle},
+	}
+	for _, c := range errs {
+		_, err := Markup(c.in)
+		var merr *MarkupError
+		if !errors.As(err, &merr) || !errors.Is(err, c.err) {
+			t.Errorf("%q: expected %v, got %v", c.in, c.err, err)
+		}
+	}
+	if _, err := Markup("[bold purple]x"); !strings.Contains(err.Error(), `"purple"`) {
+		t.Errorf("error does not name unknown word: %v", err)
+	}
+}
+
+func Test_PrintSuiteMarkup(t *testing.T) {
+	var suite PrintSuite
+	suite.SetColorProfile(ANSI16)
+	suite.Configure(PrinterConfig{Name: "error", Color: "red", Prefix: "<", Suffix: ">"})
+	got, err := suite.Markup("[error bold]%s[/] ok", "[x]")
+	if want := Red + Bold + "<[x]>" + Reset + " ok"; got != want || err != nil {
+		t.Errorf("got %q, %v want %q", got, err, want)
+	}
+	suite.Configure(PrinterConfig{Name: "pct", Prefix: "100% ", Suffix: " %d"})
+	if got, err := suite.Markup("[pct]x[/] %d", 5); got != "100% x %d 5" || err != nil {
+		t.Errorf("percent in prefix and suffix: got %q, %v", got, err)
+	}
+	if got, err := suite.Markup("[pct]x %d", 5); got != "100% x 5 %d" || err != nil {
+		t.Errorf("percent in suffix of unclosed tag: got %q, %v", got, err)
+	}
+	suite.Configure(PrinterConfig{Name: "quote", Color: "red", Suffix: " 50%"})
+	if got, err := suite.Markup("[quote]x"); got != Red+"x 50%"+Reset || err != nil {
+		t.Errorf("unclosed styled tag: got %q, %v", got, err)
+	}
+}
+
+func Test_ParseStyle(t *testing.T) {
+	cases := []struct {
+		spec string
+		conf PrinterConfig
+		str  string
+	}{
+		{"bold italic brightred on blue", PrinterConfig{Bold: true, Italic: true, Color: "brightred", Background: "blue"}, "bold italic brightred on blue"},
+		{"fg:208 bg:#112233 underline", PrinterConfig{Color: 208, Background: "#112233", Underline: true}, "underline fg:208 bg:#112233"},
+		{"Bright-Red ON Grey", PrinterConfig{Color: "brightred", Background: "brightblack"}, "brightred on brightblack"},
+		{"dim underline:curly ul:#F00 bright_blue", PrinterConfig{Faint: true, Underline: true, UnderlineStyle: UnderlineCurly, UnderlineColor: "#f00", Color: "brightblue"}, "faint underline:curly brightblue ul:#f00"},
+		{"  ", PrinterConfig{}, ""},
+	}
+	for _, c := range cases {
+		conf, err := ParseStyle(c.spec)
+		if err != nil {
+			t.Errorf("%q: %v", c.spec, err)
+			continue
+		}
+		if conf != c.conf {
+			t.Errorf("%q: got %+v want %+v", c.spec, conf, c.conf)
+		}
+		if conf.String() != c.str {
+			t.Errorf("%q: String() = %q want %q", c.spec, conf.String(), c.str)
+		}
+		if again, err := ParseStyle(conf.String()); err != nil || again != conf {
+			t.Errorf("%q does not round-trip: %+v, %v", c.spec, again, err)
+		}
+	}
+	// configs which ParseStyle does not return itself must round-trip to the same style
+	roundTrip := []struct {
+		conf PrinterConfig
+		str  string
+	}{
+		{PrinterConfig{UnderlineStyle: UnderlineCurly}, "underline:curly"},
+		{PrinterConfig{Bold: true, UnderlineStyle: UnderlineDouble, Color: "red"}, "bold underline:double red"},
+	}
+	for _, c := range roundTrip {
+		if s := c.conf.String(); s != c.str {
+			t.Errorf("%+v: String() = %q want %q", c.conf, s, c.str)
+		}
+		if again, err := ParseStyle(c.conf.String()); err != nil || again.modes() != c.conf.modes() {
+			t.Errorf("%+v does not round-trip: %+v, %v", c.conf, again, err)
+		}
+	}
+	if s := (PrinterConfig{Color: RGB{1, 2, 3}}).String(); s != "fg:#010203" {
+		t.Errorf("RGB String(): %q", s)
+	}
+	for _, spec := range []string{"bold purple", "on", "fg:nosuch", "underline:wavy", "xx:red", "on 300"} {
+		if _, err := ParseStyle(spec); !errors.Is(err, ErrUnknownStyle) {
+			t.Errorf("%q: expected ErrUnknownStyle, got %v", spec, err)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package termtools
+
+import "fmt"
+
+// MouseMode is a set of mouse reporting modes which may be combined with "|".
+type MouseMode int
+
+const (
+	// MouseX10 reports button presses only.
+	MouseX10 MouseMode = 1 << iota
+	// MouseNormal reports button presses and releases and wheel.
+	MouseNormal
+	// MouseButtonEvent additionally reports motion while a button is pressed (dragging).
+	MouseButtonEvent
+	// MouseAnyEvent reports all motion even if no button is pressed.
+	MouseAnyEvent
+	// MouseSGR switches on extended (SGR 1006) encoding of reports. It should be combined with
+	// other modes. Without it coordinates larger than 223 can not be reported and
+	// released button is not known.
+	MouseSGR
+)
+
+// mouseModeCodes hold numbers of private modes switching mouse reporting modes on and off.
+var mouseModeCodes = []struct {
+	mode MouseMode
+	code int
+}{{MouseX10, 9}, {MouseNormal, 1000}, {MouseButtonEvent, 1002}, {MouseAnyEvent, 1003}, {MouseSGR, 1006}}
+
+// EnableMouse switches requested mouse reporting modes on. Reports are decoded into MouseEvent
+// by KeyReader. Most programs need MouseNormal|MouseSGR or MouseButtonEvent|MouseSGR.
+func (t *Terminal) EnableMouse(mode MouseMode) {
+	for _, m := range mouseModeCodes {
+		if mode&m.mode != 0 {
+			t.Printf(Esc+"[?%dh", m.code)
+		}
+	}
+}
+
+// DisableMouse switches all mouse reporting modes off.
+func (t *Terminal) DisableMouse() {
+	for i := len(mouseModeCodes) - 1; i >= 0; i-- {
+		t.Printf(Esc+"[?%dl", mouseModeCodes[i].code)
+	}
+}
+
+// MouseButton identifies mouse button.
+type MouseButton int
+
+const (
+	// MouseNoButton is reported with motion when no button is pressed and with
+	// release in legacy encoding which does not tell which button was released.
+	MouseNoButton MouseButton = iota
+	MouseLeft
+	MouseMiddle
+	MouseRight
+	MouseWheelUp
+	MouseWheelDown
+	MouseWheelLeft
+	MouseWheelRight
+	MouseBackward
+	MouseForward
+)
+
+var mouseButtonNames = map[MouseButton]string{
+	MouseNoButton: "none", MouseLeft: "left", MouseMiddle: "middle", MouseRight: "right",
+	MouseWheelUp: "wheelup", MouseWheelDown: "wheeldown", MouseWheelLeft: "wheelleft", MouseWheelRight: "wheelright",
+	MouseBackward: "backward", MouseForward: "forward"}
+
+// String implements fmt.Stringer.
+func (b MouseButton) String() string {
+	if name, ok := mouseButtonNames[b]; ok {
+		return name
+	}
+	return "MouseButton(unknown)"
+}
+
+// MouseAction is a kind of mouse event.
+type MouseAction int
+
+const (
+	MousePress MouseAction = iota
+	MouseRelease
+	MouseMotion
+)
+
+var mouseActionNames = map[MouseAction]string{MousePress: "press", MouseRelease: "release", MouseMotion: "motion"}
+
+// String implements fmt.Stringer.
+func (a MouseAction) String() string {
+	if name, ok := mouseActionNames[a]; ok {
+		return name
+	}
+	return "MouseAction(unknown)"
+}
+
+// MouseEvent is a mouse report. X is column and Y is row of mouse pointer as accepted by
+// MoveCursorTo, so that MoveCursorTo(ev.X, ev.Y) moves cursor to the cell under the pointer.
+// Upper left cell is (1, 1). Wheel is reported as press of wheel buttons.
+type MouseEvent struct {
+	X, Y      int
+	Button    MouseButton
+	Action    MouseAction
+	Modifiers Modifier
+}
+
+func (MouseEvent) isEvent() {}
+
+// String implements fmt.Stringer. It returns description like "ctrl+left press 10,5".
+func (e MouseEvent) String() string {
+	s := fmt.Sprintf("%v %v %d,%d", e.Button, e.Action, e.X, e.Y)
+	if mods := e.Modifiers.String(); mods != "" {
+		return mods + "+" + s
+	}
+	return s
+}
+
+// decodeMouse decodes button code and coordinates of mouse report. In SGR encoding
+// release is marked by final byte, in legacy encoding by button code 3.
+func decodeMouse(code, x, y int, release bool) MouseEvent {
+	ev := MouseEvent{X: x, Y: y}
+	if code&4 != 0 {
+		ev.Modifiers |= ModShift
+	}
+	if code&8 != 0 {
+		ev.Modifiers |= ModAlt
+	}
+	if code&16 != 0 {
+		ev.Modifiers |= ModCtrl
+	}
+	button := code & 3
+	switch {
+	case code&128 != 0:
+		ev.Button = MouseBackward + MouseButton(button)
+	case code&64 != 0:
+		ev.Button = MouseWheelUp + MouseButton(button)
+	case button == 3:
+		ev.Button = MouseNoButton
+		if code&32 == 0 {
+			release = true
+		}
+	default:
+		ev.Button = MouseLeft + MouseButton(button)
+	}
+	switch {
+	case code&32 != 0:
+		ev.Action = MouseMotion
+	case release:
+		ev.Action = MouseRelease
+	}
+	return ev
+}
@@ -0,0 +1,161 @@
+package termtools
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Palette holds RGB values of 256 colors palette indexed by color ID. IDs 0 to 15 are
+// base colors, IDs 16 to 231 form 6x6x6 color cube and IDs 232 to 255 form grayscale ramp.
+type Palette [256]RGB
+
+const (
+	// CubeStart is ID of the first color of 6x6x6 color cube.
+	CubeStart = 16
+	// GrayscaleStart is ID of the first color of grayscale ramp.
+	GrayscaleStart = 232
+)
+
+// xtermColors holds RGB values of default xterm 256 colors palette.
+var xtermColors = func() (colors Palette) {
+	base := []RGB{
+		{0, 0, 0}, {128, 0, 0}, {0, 128, 0}, {128, 128, 0}, {0, 0, 128}, {128, 0, 128}, {0, 128, 128}, {192, 192, 192},
+		{128, 128, 128}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0}, {0, 0, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255}}
+	copy(colors[:], base)
+	for i := 0; i < 216; i++ {
+		colors[CubeStart+i] = RGB{cubeLevels[i/36], cubeLevels[i/6%6], cubeLevels[i%6]}
+	}
+	for i := 0; i < 24; i++ {
+		v := uint8(8 + 10*i)
+		colors[GrayscaleStart+i] = RGB{v, v, v}
+	}
+	return
+}()
+
+// cubeLevels holds intensities of color components in 6x6x6 color cube.
+var cubeLevels = []uint8{0, 95, 135, 175, 215, 255}
+
+// XtermPalette returns default xterm 256 colors palette. Terminals may redefine
+// its colors (especially 16 base colors), so actual output may differ.
+func XtermPalette() Palette {
+	return xtermColors
+}
+
+// RGB returns RGB value of color with ID id. It returns ErrUnknownColor if id is out of range [0;255].
+func (p Palette) RGB(id int) (RGB, error) {
+	if id < 0 || id > 255 {
+		return RGB{}, ErrUnknownColor
+	}
+	return p[id], nil
+}
+
+// Nearest returns ID of color from palette which is closest to c. All 256 colors
+// are considered. See also NearestANSI256 and NearestANSI16.
+func (p Palette) Nearest(c RGB) int {
+	return nearestColor(c, p[:])
+}
+
+// Lookup returns ID of color set by escape sequence code as returned by GetColorCode or GetBackgroundCode.
+// Codes of 16 base colors (including legacy bright codes, see SetBoldAsBright), 256 colors and underline
+// colors are recognized. For 24-bit color codes ID of the nearest color from palette is returned.
+// Name holds color name for base colors and is empty otherwise. ErrUnknownColor is returned if code
+// does not set color.
+func (p Palette) Lookup(code string) (id int, name string, err error) {
+	if !strings.HasPrefix(code, Esc+"[") || !strings.HasSuffix(code, "m") {
+		return 0, "", ErrUnknownColor
+	}
+	var params []int
+	for _, param := range strings.Split(code[len(Esc+"["):len(code)-1], ";") {
+		n, err := strconv.Atoi(param)
+		if err != nil || n < 0 || n > 255 {
+			return 0, "", ErrUnknownColor
+		}
+		params = append(params, n)
+	}
+	id = -1
+	switch {
+	case len(params) == 1:
+		id = baseColorID(params[0])
+	case len(params) == 2 && params[1] == 1:
+		// legacy bright color
+		if id = baseColorID(params[0]); id >= 8 {
+			id = -1
+		} else if id >= 0 {
+			id += 8
+		}
+	case len(params) == 3 && isExtendedColor(params[0]) && params[1] == 5:
+		id = params[2]
+	case len(params) == 5 && isExtendedColor(params[0]) && params[1] == 2:
+		id = p.Nearest(RGB{uint8(params[2]), uint8(params[3]), uint8(params[4])})
+	}
+	if id < 0 {
+		return 0, "", ErrUnknownColor
+	}
+	name, _ = ColorName(id)
+	return id, name, nil
+}
+
+// baseColorID returns ID of base color set by SGR parameter or -1.
+func baseColorID(param int) int {
+	switch {
+	case param >= 30 && param <= 37:
+		return param - 30
+	case param >= 40 && param <= 47:
+		return param - 40
+	case param >= 90 && param <= 97:
+		return param - 90 + 8
+	case param >= 100 && param <= 107:
+		return param - 100 + 8
+	}
+	return -1
+}
+
+func isExtendedColor(param int) bool {
+	return param == 38 || param == 48 || param == 58
+}
+
+// ColorName returns name of base color with ID id (in range [0;15]) as accepted by Printer SetColor method.
+// It returns false if id is not ID of base color.
+func ColorName(id int) (string, bool) {
+	if id < 0 || id >= len(ansiNames) {
+		return "", false
+	}
+	return ansiNames[id], true
+}
+
+// CubeID returns ID of color in 6x6x6 color cube with coordinates r, g and b in range [0;5].
+func CubeID(r, g, b int) (int, error) {
+	for _, v := range []int{r, g, b} {
+		if v < 0 || v > 5 {
+			return 0, ErrUnknownColor
+		}
+	}
+	return CubeStart + 36*r + 6*g + b, nil
+}
+
+// CubeCoordinates returns coordinates of color in 6x6x6 color cube. It returns false
+// if id is not in range [16;231].
+func CubeCoordinates(id int) (r, g, b int, ok bool) {
+	if id < CubeStart || id >= GrayscaleStart {
+		return 0, 0, 0, false
+	}
+	id -= CubeStart
+	return id / 36, id / 6 % 6, id % 6, true
+}
+
+// GrayscaleID returns ID of color with step in range [0;23] of grayscale ramp. Step 0 is the darkest.
+func GrayscaleID(step int) (int, error) {
+	if step < 0 || step > 23 {
+		return 0, ErrUnknownColor
+	}
+	return GrayscaleStart + step, nil
+}
+
+// GrayscaleStep returns step of grayscale ramp for color with ID id. It returns false
+// if id is not in range [232;255].
+func GrayscaleStep(id int) (int, bool) {
+	if id < GrayscaleStart || id > 255 {
+		return 0, false
+	}
+	return id - GrayscaleStart, true
+}
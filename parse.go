@@ -0,0 +1,268 @@
+package termtools
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownStyle is returned when style specification contains unknown word.
+var ErrUnknownStyle = errors.New("error: unknown style")
+
+// attributeNames maps words used in style specifications to attributes.
+var attributeNames = map[string]Attribute{
+	"bold":          AttrBold,
+	"faint":         AttrFaint,
+	"dim":           AttrFaint,
+	"italic":        AttrItalic,
+	"underline":     AttrUnderline,
+	"blinking":      AttrBlinking,
+	"blink":         AttrBlinking,
+	"reversed":      AttrReversed,
+	"reverse":       AttrReversed,
+	"hidden":        AttrHidden,
+	"conceal":       AttrHidden,
+	"strikethrough": AttrStrikethrough,
+	"strike":        AttrStrikethrough,
+	"overline":      AttrOverline}
+
+var underlineStyleNames = []string{"single", "double", "curly", "dotted", "dashed"}
+
+// colorAliases maps alternative color names to names known to the package.
+var colorAliases = map[string]string{
+	"gray":      "brightblack",
+	"grey":      "brightblack",
+	"darkgray":  "brightblack",
+	"darkgrey":  "brightblack",
+	"lightgray": "white",
+	"lightgrey": "white"}
+
+// ParseStyle parses human-friendly style specification and returns PrinterConfig.
+// Specification consists of space separated case insensitive words:
+//
+//	attribute names: bold, faint (dim), italic, underline, blinking (blink),
+//	reversed (reverse), hidden (conceal), strikethrough (strike), overline;
+//	autofg picks readable text color for background (see PrinterConfig AutoForeground);
+//	underline styles: underline:single, underline:double, underline:curly, underline:dotted, underline:dashed;
+//	colors: color names, hex values ("#112233") or color IDs ("208");
+//	"on" followed by color sets background;
+//	"fg:", "bg:" and "ul:" followed by color set text, background and underline color.
+//
+// Color names may be spelled as "brightred", "bright-red" or "bright_red", "gray" and "grey" are
+// aliases of "brightblack". Examples of valid specifications:
+//
+//	"bold italic brightred on blue"
+//	"fg:208 bg:#112233 underline"
+//
+// Colors in returned config are color IDs (int), color names or hex values (string).
+// If specification contains unknown word, ParseStyle returns an error wrapping ErrUnknownStyle.
+// ParseStyle is the inverse of PrinterConfig String method.
+func ParseStyle(spec string) (PrinterConfig, error) {
+	conf, _, err := parseStyleSpec(spec, nil)
+	return conf, err
+}
+
+// parseStyleSpec parses style specification (see ParseStyle). If lookup is not nil it is used
+// to resolve words which are neither attributes nor colors into named styles which are
+// merged into returned named Style.
+func parseStyleSpec(spec string, lookup func(name string) (Style, bool)) (conf PrinterConfig, named Style, err error) {
+	words := strings.Fields(spec)
+	for i := 0; i < len(words); i++ {
+		word := strings.ToLower(words[i])
+		if attr, ok := attributeNames[word]; ok {
+			conf.setAttribute(attr)
+			continue
+		}
+		if word == "autofg" {
+			conf.AutoForeground = true
+			continue
+		}
+		if key, value, ok := splitSpecWord(word); ok {
+			if err = conf.setSpecValue(key, value); err != nil {
+				return PrinterConfig{}, Style{}, err
+			}
+			continue
+		}
+		if word == "on" {
+			if i+1 == len(words) {
+				return PrinterConfig{}, Style{}, fmt.Errorf("%w: missing color after %q", ErrUnknownStyle, words[i])
+			}
+			i++
+			if err = conf.setSpecValue("bg", strings.ToLower(words[i])); err != nil {
+				return PrinterConfig{}, Style{}, err
+			}
+			continue
+		}
+		if c, ok := specColor(word); ok {
+			conf.Color = c
+			continue
+		}
+		if lookup != nil {
+			if s, ok := lookup(words[i]); ok {
+				named = named.Merge(s)
+				continue
+			}
+		}
+		return PrinterConfig{}, Style{}, fmt.Errorf("%w %q", ErrUnknownStyle, words[i])
+	}
+	return
+}
+
+func splitSpecWord(word string) (key, value string, ok bool) {
+	i := strings.IndexByte(word, ':')
+	if i < 0 {
+		return "", "", false
+	}
+	return word[:i], word[i+1:], true
+}
+
+func (conf *PrinterConfig) setSpecValue(key, value string) error {
+	if key == "underline" {
+		for style, name := range underlineStyleNames {
+			if name == value {
+				conf.Underline = true
+				conf.UnderlineStyle = UnderlineStyle(style)
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: unknown underline style %q", ErrUnknownStyle, value)
+	}
+	c, ok := specColor(value)
+	if !ok {
+		return fmt.Errorf("%w: unknown color %q in %q", ErrUnknownStyle, value, key+":"+value)
+	}
+	switch key {
+	case "fg":
+		conf.Color = c
+	case "bg":
+		conf.Background = c
+	case "ul":
+		conf.UnderlineColor = c
+	default:
+		return fmt.Errorf("%w %q", ErrUnknownStyle, key+":"+value)
+	}
+	return nil
+}
+
+func (conf *PrinterConfig) setAttribute(attr Attribute) {
+	switch attr {
+	case AttrBold:
+		conf.Bold = true
+	case AttrFaint:
+		conf.Faint = true
+	case AttrItalic:
+		conf.Italic = true
+	case AttrUnderline:
+		conf.Underline = true
+	case AttrBlinking:
+		conf.Blinking = true
+	case AttrReversed:
+		conf.Reversed = true
+	case AttrHidden:
+		conf.Hidden = true
+	case AttrStrikethrough:
+		conf.Strikethrough = true
+	case AttrOverline:
+		conf.Overline = true
+	}
+}
+
+// specColor converts word of style specification to valid color identifier:
+// numbers are converted to int, names are normalized.
+func specColor(word string) (interface{}, bool) {
+	var c interface{}
+	if id, err := strconv.Atoi(word); err == nil {
+		c = id
+	} else {
+		c = normalizeColorName(word)
+	}
+	if _, err := parseColor(c); err != nil {
+		return nil, false
+	}
+	return c, true
+}
+
+// normalizeColorName converts name to lower case, removes "-" and "_" and
+// resolves aliases.
+func normalizeColorName(name string) string {
+	name = strings.ToLower(name)
+	if isHex(name) {
+		return name
+	}
+	name = strings.NewReplacer("-", "", "_", "").Replace(name)
+	if alias, ok := colorAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// String returns style specification of config as accepted by ParseStyle,
+// for example "bold underline:curly brightred on blue". Name, Prefix, Suffix and Output fields
+// are not included. Colors of type RGB and color.Color are output as hex values, CompleteColor is
+// output as its richest variant and AdaptiveColor as its dark variant.
+func (conf PrinterConfig) String() string {
+	var words []string
+	modes := []struct {
+		on   bool
+		name string
+	}{
+		{conf.Bold, "bold"}, {conf.Faint, "faint"}, {conf.Italic, "italic"},
+		// underline style other than single switches underline on by itself, see PrinterConfig
+		{conf.Underline || conf.UnderlineStyle != UnderlineSingle, "underline"},
+		{conf.Blinking, "blinking"}, {conf.Reversed, "reversed"}, {conf.Hidden, "hidden"},
+		{conf.Strikethrough, "strikethrough"}, {conf.Overline, "overline"}, {conf.AutoForeground, "autofg"}}
+	for _, mode := range modes {
+		if !mode.on {
+			continue
+		}
+		if mode.name == "underline" && conf.UnderlineStyle > UnderlineSingle && int(conf.UnderlineStyle) < len(underlineStyleNames) {
+			words = append(words, "underline:"+underlineStyleNames[conf.UnderlineStyle])
+			continue
+		}
+		words = append(words, mode.name)
+	}
+	if conf.Color != nil {
+		if name, ok := conf.Color.(string); ok && !isHex(name) {
+			words = append(words, name)
+		} else {
+			words = append(words, "fg:"+specColorString(conf.Color))
+		}
+	}
+	if conf.Background != nil {
+		if name, ok := conf.Background.(string); ok && !isHex(name) {
+			words = append(words, "on", name)
+		} else {
+			words = append(words, "bg:"+specColorString(conf.Background))
+		}
+	}
+	if conf.UnderlineColor != nil {
+		words = append(words, "ul:"+specColorString(conf.UnderlineColor))
+	}
+	return strings.Join(words, " ")
+}
+
+func specColorString(c interface{}) string {
+	switch v := c.(type) {
+	case RGB:
+		return v.Hex()
+	case CompleteColor:
+		// specification can hold only one color, the richest is used
+		for _, variant := range []interface{}{v.TrueColor, v.ANSI256, v.ANSI16} {
+			if variant != nil {
+				return specColorString(variant)
+			}
+		}
+	case AdaptiveColor:
+		// the dark variant is used as terminals usually have dark background
+		for _, variant := range []interface{}{v.Dark, v.Light} {
+			if variant != nil {
+				return specColorString(variant)
+			}
+		}
+	case color.Color:
+		return rgbFromColor(v).Hex()
+	}
+	return fmt.Sprint(c)
+}
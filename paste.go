@@ -0,0 +1,61 @@
+package termtools
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// Markers which terminal puts around pasted text when bracketed paste is on.
+const (
+	pasteStart = Esc + "[200~"
+	pasteEnd   = Esc + "[201~"
+)
+
+// EnableBracketedPaste asks terminal to mark pasted text. KeyReader then reports
+// pasted text as single PasteEvent rather than separate keys, so that newlines in
+// pasted text are not taken for Enter.
+func (t *Terminal) EnableBracketedPaste() {
+	t.Print(Esc + "[?2004h")
+}
+
+// DisableBracketedPaste switches bracketed paste off.
+func (t *Terminal) DisableBracketedPaste() {
+	t.Print(Esc + "[?2004l")
+}
+
+// PasteEvent holds text pasted into terminal with bracketed paste on (see EnableBracketedPaste).
+// Line endings in Text are converted to "\n".
+type PasteEvent struct {
+	Text string
+}
+
+func (PasteEvent) isEvent() {}
+
+// String implements fmt.Stringer. It returns text quoted like "paste \"text\"".
+func (e PasteEvent) String() string {
+	return "paste " + strconv.Quote(e.Text)
+}
+
+// decodePaste decodes pasted text following start marker which takes first n bytes of b.
+// If end marker is not found decodePaste returns 0 unless final is true, in which case
+// the rest of b is taken as pasted text.
+func decodePaste(b []byte, n int, final bool) (Event, int) {
+	text := b[n:]
+	if i := bytes.Index(text, []byte(pasteEnd)); i >= 0 {
+		return PasteEvent{Text: normalizeNewlines(string(text[:i]))}, n + i + len(pasteEnd)
+	}
+	if !final {
+		return nil, 0
+	}
+	return PasteEvent{Text: normalizeNewlines(string(text))}, len(b)
+}
+
+// isPasting reports whether b starts with pasted text.
+func isPasting(b []byte) bool {
+	return bytes.HasPrefix(b, []byte(pasteStart))
+}
+
+func normalizeNewlines(s string) string {
+	return strings.Replace(strings.Replace(s, "\r\n", "\n", -1), "\r", "\n", -1)
+}
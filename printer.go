@@ -4,25 +4,26 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 )
 
 var (
 	ErrFailedToSetColor      = errors.New("error: failed to set printer color")
 	ErrFailedToSetBackground = errors.New("error: failed to set printer background")
+	// ErrFailedToSetUnderlineColor is returned when setting invalid underline color.
+	ErrFailedToSetUnderlineColor = errors.New("error: failed to set printer underline color")
 )
 
 // Printer holds color and style settings and implements most methods as in fmt module like Print,
 // Println, Sprint etc. adding color and styles to the input values.
 type Printer struct {
-	color      string
-	background string
-	prefix     string
-	suffix     string
-	bold       bool
-	underline  bool
-	reversed   bool
-	blinking   bool
-	name       string
+	style Style
+	name  string
+	// profile is used instead of detected color profile if hasProfile is true
+	profile    ColorProfile
+	hasProfile bool
+	// out is the output destination, nil means os.Stdout
+	out io.Writer
 }
 
 // PrinterConfig describes configuration of Printer.
@@ -33,18 +34,39 @@ type PrinterConfig struct {
 	//
 	// When setting up a Printer instance with NewPrinter() Name field may be omitted.
 	Name string
-	// Color and Backgrount fields may hold color name (as string) or color ID in range
-	// [0;255]. See package docs for list of available color names.
+	// Color and Backgrount fields may hold color name or hex value (as string), color ID in range
+	// [0;255], RGB or color.Color. See package docs for list of available color names.
 	Color      interface{}
 	Background interface{}
-	// Bold, Underline, Reversed, Blinking switch relevant printer modes on if set to true.
-	Bold      bool
-	Underline bool
-	Reversed  bool
-	Blinking  bool
+	// Bold, Underline, Reversed, Blinking, Faint, Italic, Hidden, Strikethrough and Overline
+	// switch relevant printer modes on if set to true.
+	Bold          bool
+	Underline     bool
+	Reversed      bool
+	Blinking      bool
+	Faint         bool
+	Italic        bool
+	Hidden        bool
+	Strikethrough bool
+	Overline      bool
+	// UnderlineStyle sets style of underline. Setting style other than UnderlineSingle
+	// switches underline on even if Underline is false.
+	UnderlineStyle UnderlineStyle
+	// UnderlineColor sets color of underline. It accepts the same values as Color.
+	UnderlineColor interface{}
+	// AutoForeground makes printer pick readable text color (black or white) for its background
+	// if Color is not set. See ReadableForeground.
+	AutoForeground bool
 	// Prefix and suffix are added to output if they are not empty strings.
 	Prefix string
 	Suffix string
+	// Output is the destination of Print, Printf, Println methods and cursor
+	// movements. If nil, os.Stdout is used.
+	Output io.Writer
+	// Extends holds name of parent printer when config is passed to PrintSuite Configure method.
+	// Fields which are not set (hold zero values) are taken from parent. Note that modes switched on in
+	// parent can not be switched off by child. Extends is ignored by NewPrinter.
+	Extends string
 }
 
 // NewPrinter takes PrinterConfig and returns pointer to Printer.
@@ -53,9 +75,8 @@ type PrinterConfig struct {
 // Check for errors to make sure that returned Printer is fully configured.
 func NewPrinter(conf PrinterConfig) (p *Printer, err error) {
 	p = &Printer{}
-	// TODO: Probably rewrite two blocks below using unexported funcs.
-	p.bold, p.underline, p.reversed, p.blinking = conf.Bold, conf.Underline, conf.Reversed, conf.Blinking
-	p.prefix, p.suffix = conf.Prefix, conf.Suffix
+	p.style = conf.modes()
+	p.out = conf.Output
 	if conf.Color != nil {
 		if err = p.SetColor(conf.Color); err != nil {
 			return
@@ -66,92 +87,145 @@ func NewPrinter(conf PrinterConfig) (p *Printer, err error) {
 			return
 		}
 	}
+	if conf.UnderlineColor != nil {
+		if err = p.SetUnderlineColor(conf.UnderlineColor); err != nil {
+			return
+		}
+	}
 	err = nil
 	return
 }
 
+// Style returns Style with color, modes, prefix and suffix of PrinterConfig.
+// If config holds invalid color(s) the error is available from Err method of returned Style.
+func (conf PrinterConfig) Style() Style {
+	s := conf.modes()
+	if conf.Color != nil {
+		s = s.Foreground(conf.Color)
+	}
+	if conf.Background != nil {
+		s = s.Background(conf.Background)
+	}
+	if conf.UnderlineColor != nil {
+		s = s.UnderlineColor(conf.UnderlineColor)
+	}
+	return s
+}
+
+// modes returns Style with modes, prefix and suffix of PrinterConfig.
+// Only modes which are on are set in the Style.
+func (conf PrinterConfig) modes() (s Style) {
+	modes := []struct {
+		on   bool
+		attr Attribute
+	}{
+		{conf.Bold, AttrBold}, {conf.Underline, AttrUnderline}, {conf.Reversed, AttrReversed},
+		{conf.Blinking, AttrBlinking}, {conf.Faint, AttrFaint}, {conf.Italic, AttrItalic},
+		{conf.Hidden, AttrHidden}, {conf.Strikethrough, AttrStrikethrough}, {conf.Overline, AttrOverline}}
+	for _, mode := range modes {
+		if mode.on {
+			s = s.Set(mode.attr, true)
+		}
+	}
+	if conf.UnderlineStyle != UnderlineSingle {
+		s = s.UnderlineStyle(conf.UnderlineStyle)
+	}
+	if conf.AutoForeground {
+		s = s.AutoForeground(true)
+	}
+	if conf.Prefix != "" {
+		s = s.Prefix(conf.Prefix)
+	}
+	if conf.Suffix != "" {
+		s = s.Suffix(conf.Suffix)
+	}
+	return
+}
+
 // Printing methods
 
 // Errorf formats according to a format specifier and returns the string as a value that satisfies error.
 func (p *Printer) Errorf(format string, a ...interface{}) error {
-	out := p.processString(format)
+	out := p.processString(p.output(), format)
 	return fmt.Errorf(out, a...)
 }
 
 // Fprint formats using the default formats for its operands and writes to w. Spaces are added between
 // operands when neither is a string. It returns the number of bytes written and any write error encountered.
 func (p *Printer) Fprint(w io.Writer, a ...interface{}) (n int, err error) {
-	out := p.processString(a...)
+	out := p.processString(w, a...)
 	return fmt.Fprint(w, out)
 }
 
 // Fprintf formats according to a format specifier and writes to w. It returns the number of bytes written
 // and any write error encountered.
 func (p *Printer) Fprintf(w io.Writer, format string, a ...interface{}) (n int, err error) {
-	out := p.processString(format)
+	out := p.processString(w, format)
 	return fmt.Fprintf(w, out, a...)
 }
 
 // Fprintln formats using the default formats for its operands and writes to w.
 // Spaces are always added between operands and a newline is appended. It returns the number of bytes written and any write error encountered.
 func (p *Printer) Fprintln(w io.Writer, a ...interface{}) (n int, err error) {
-	out := p.processString(a...)
+	out := p.processString(w, a...)
 	return fmt.Fprintln(w, out)
 }
 
-// Print formats using the default formats for its operands and writes to standard output.
+// Print formats using the default formats for its operands and writes to Printer output (standard output by default).
 // Spaces are added between operands when neither is a string. It returns the number of bytes written and any write error encountered.
 func (p *Printer) Print(a ...interface{}) (n int, err error) {
-	out := p.processString(a...)
-	return fmt.Print(out)
+	out := p.processString(p.output(), a...)
+	return fmt.Fprint(p.output(), out)
 }
 
-// Printf formats according to a format specifier and writes to standard output.
+// Printf formats according to a format specifier and writes to Printer output (standard output by default).
 // It returns the number of bytes written and any write error encountered.
 func (p *Printer) Printf(format string, a ...interface{}) (n int, err error) {
-	out := p.processString(format)
-	return fmt.Printf(out, a...)
+	out := p.processString(p.output(), format)
+	return fmt.Fprintf(p.output(), out, a...)
 }
 
-// Println formats using the default formats for its operands and writes to standard output.
+// Println formats using the default formats for its operands and writes to Printer output (standard output by default).
 // Spaces are always added between operands and a newline is appended. It returns the number of bytes written and any write error encountered.
 func (p *Printer) Println(a ...interface{}) (n int, err error) {
-	out := p.processString(a...)
-	return fmt.Println(out)
+	out := p.processString(p.output(), a...)
+	return fmt.Fprintln(p.output(), out)
 }
 
 // Sprint formats using the default formats for its operands and returns the resulting string.
 // Spaces are added between operands when neither is a string.
 func (p *Printer) Sprint(a ...interface{}) string {
-	out := p.processString(a...)
+	out := p.processString(p.output(), a...)
 	return fmt.Sprint(out)
 }
 
 // Sprintf formats according to a format specifier and returns the resulting string.
 func (p *Printer) Sprintf(format string, a ...interface{}) string {
-	out := p.processString(format)
+	out := p.processString(p.output(), format)
 	return fmt.Sprintf(out, a...)
 }
 
 // Sprintln formats using the default formats for its operands and returns the resulting string.
 // Spaces are always added between operands and a newline is appended.
 func (p *Printer) Sprintln(a ...interface{}) string {
-	out := p.processString(a...)
+	out := p.processString(p.output(), a...)
 	return fmt.Sprintln(out)
 }
 
 // Color methods
 
-// SetColor sets color of printer. Argument "color" must be either string or int.
+// SetColor sets color of printer. Argument "color" must be either string, int, RGB or color.Color.
 // Valid color names are: "black", "blue", "cyan", "green", "magenta",
 // "red", "white", "yellow", "brightblack", "brightblue", "brightcyan",
 // "brightgreen", "brightmagenta", "brightred", "brightwhite", "brightyellow".
+// Hex strings in form "#1e90ff" or "#abc" are also accepted.
 // Valid numbers are from 0 to 255 inclusive.
 // If color is not known, is empty, or int is out of range the method
 // will return an error and currently set Printer color will not be changed.
 func (p *Printer) SetColor(color interface{}) error {
-	if code, err := getColorCode(color); err == nil {
-		p.color = code
+	if c, err := parseColor(color); err == nil {
+		p.style.fg = c
+		p.style.set |= setForeground
 		return nil
 	}
 	return ErrFailedToSetColor
@@ -160,51 +234,122 @@ func (p *Printer) SetColor(color interface{}) error {
 // SetBackground sets background color of printer. Argument color is the same as in SetColor method.
 // will return an error and currently set Printer color will not be changed.
 func (p *Printer) SetBackground(color interface{}) error {
-	if code, err := getBackgroundCode(color); err == nil {
-		p.background = code
+	if c, err := parseColor(color); err == nil {
+		p.style.bg = c
+		p.style.set |= setBackground
 		return nil
 	}
 	return ErrFailedToSetBackground
 }
 
+// SetUnderlineColor sets color of underline. Argument color is the same as in SetColor method.
+// Underline color is only visible if underline mode is on. Note that underline color is not supported by all terminals.
+func (p *Printer) SetUnderlineColor(color interface{}) error {
+	if c, err := parseColor(color); err == nil {
+		p.style.ul = c
+		p.style.set |= setUnderlineColor
+		return nil
+	}
+	return ErrFailedToSetUnderlineColor
+}
+
+// SetColorProfile makes Printer render colors with requested profile instead of
+// the one detected for output. Colors which are not supported by profile are replaced
+// with the nearest supported ones. NoColor profile disables all escape sequences.
+func (p *Printer) SetColorProfile(profile ColorProfile) {
+	p.profile, p.hasProfile = profile, true
+}
+
+// colorProfile returns profile set with SetColorProfile or profile detected for w.
+func (p *Printer) colorProfile(w io.Writer) ColorProfile {
+	if p.hasProfile {
+		return p.profile
+	}
+	return detectColorProfile(w)
+}
+
 // SetPrefixSuffix configures Printer to always preceed output with prefix
 // and end output with suffix. Printer color and style settings apply to prefix and suffix.
 func (p *Printer) SetPrefixSuffix(prefix, suffix string) {
-	p.prefix, p.suffix = prefix, suffix
+	p.style = p.style.Prefix(prefix).Suffix(suffix)
 }
 
 // Modes Methods
 
 // ToggleBold toggles bold mode of Printer
 func (p *Printer) ToggleBold() {
-	p.bold = !p.bold
+	p.toggle(AttrBold)
 }
 
 // ToggleUnderline toggles underline mode of Printer
 func (p *Printer) ToggleUnderline() {
-	p.underline = !p.underline
+	p.toggle(AttrUnderline)
 }
 
 // ToggleReversed toggles reverse mode of Printer
 func (p *Printer) ToggleReversed() {
-	p.reversed = !p.reversed
+	p.toggle(AttrReversed)
 }
 
 // ToggleBlinking toggles blinking mode of Printer
 func (p *Printer) ToggleBlinking() {
-	p.blinking = !p.blinking
+	p.toggle(AttrBlinking)
+}
+
+// ToggleFaint toggles faint (dim) mode of Printer
+func (p *Printer) ToggleFaint() {
+	p.toggle(AttrFaint)
 }
 
-// Reset resets printer state to initial state (no color, no background, bold, underline and reversed modes turned off).
+// ToggleItalic toggles italic mode of Printer
+func (p *Printer) ToggleItalic() {
+	p.toggle(AttrItalic)
+}
+
+// ToggleHidden toggles hidden (concealed) mode of Printer
+func (p *Printer) ToggleHidden() {
+	p.toggle(AttrHidden)
+}
+
+// ToggleStrikethrough toggles strikethrough mode of Printer
+func (p *Printer) ToggleStrikethrough() {
+	p.toggle(AttrStrikethrough)
+}
+
+// ToggleOverline toggles overline mode of Printer
+func (p *Printer) ToggleOverline() {
+	p.toggle(AttrOverline)
+}
+
+// SetUnderlineStyle sets style of underline and switches underline mode on.
+func (p *Printer) SetUnderlineStyle(style UnderlineStyle) {
+	p.style = p.style.UnderlineStyle(style)
+}
+
+// ToggleAutoForeground switches automatic choice of text color on and off. If switched on
+// and color of printer is not set, black or white text color is picked depending on which one
+// is more readable on background of printer. See ReadableForeground.
+func (p *Printer) ToggleAutoForeground() {
+	p.style = p.style.AutoForeground(!p.style.autoForeground)
+}
+
+func (p *Printer) toggle(attr Attribute) {
+	p.style = p.style.Set(attr, !p.style.Has(attr))
+}
+
+// Reset resets printer state to initial state (no color, no background, all modes turned off).
 func (p *Printer) Reset() {
-	p.color = ""
-	p.background = ""
-	p.bold = false
-	p.underline = false
-	p.reversed = false
-	p.blinking = false
-	p.prefix = ""
-	p.suffix = ""
+	p.style = Style{}
+}
+
+// Style returns current Style of Printer.
+func (p *Printer) Style() Style {
+	return p.style
+}
+
+// SetStyle replaces color, modes, prefix and suffix of Printer with those of Style.
+func (p *Printer) SetStyle(s Style) {
+	p.style = s
 }
 
 // Methods implementing cursor movement
@@ -213,78 +358,89 @@ func (p *Printer) Reset() {
 // It does not return to the initial position. It returns the number of bytes written and any write error encountered.
 // See also PrintAtPositionAndReturn method.
 func (p *Printer) PrintAtPosition(column, row int, a ...interface{}) (n int, err error) {
-	out := p.processString(a...)
-	moveCursorTo(column, row)
-	return fmt.Print(out)
+	out := p.processString(p.output(), a...)
+	return p.terminal().PrintAtPosition(column, row, out)
 }
 
 // PrintAtPositionAndReturn moves cursor to specified column and row and issues Print
 // then moves cursor to initial position when method was called. It returns the number of bytes written and any write error encountered.
 func (p *Printer) PrintAtPositionAndReturn(column, row int, a ...interface{}) (n int, err error) {
-	out := p.processString(a...)
-	saveCursorPosition()
-	moveCursorTo(column, row)
-	defer restoreCursorPosition()
-	return fmt.Print(out)
+	out := p.processString(p.output(), a...)
+	return p.terminal().PrintAtPositionAndReturn(column, row, out)
+}
+
+// PrintCentered prints at specified row so that output is centered horizontally. Prefix and suffix
+// count towards width of output while escape sequences do not. It returns the number of bytes written and any write error encountered.
+func (p *Printer) PrintCentered(row int, a ...interface{}) (n int, err error) {
+	out := p.processString(p.output(), a...)
+	return p.terminal().PrintCentered(row, out)
 }
 
 // MoveTo places cursor at the specified column and row.
 func (p *Printer) MoveTo(column, row int) {
-	moveCursorTo(column, row)
+	p.terminal().MoveCursorTo(column, row)
 }
 
 // MoveHome places cursor at the top left corner of the screen.
 func (p *Printer) MoveHome() {
-	moveCursorHome()
+	p.terminal().MoveCursorHome()
 }
 
 // MoveUp moves cursor up specified number of rows.
 func (p *Printer) MoveUp(rows int) {
-	moveCursorUp(rows)
+	p.terminal().MoveCursorUp(rows)
 }
 
 // MoveDown moves cursor down specified number of rows.
 func (p *Printer) MoveDown(rows int) {
-	moveCursorDown(rows)
+	p.terminal().MoveCursorDown(rows)
 }
 
 // MoveLeft moves cursor left specified number of columns.
 func (p *Printer) MoveLeft(columns int) {
-	moveCursorLeft(columns)
+	p.terminal().MoveCursorLeft(columns)
 }
 
 // MoveRight moves cursor right specified number of columns.
 func (p *Printer) MoveRight(columns int) {
-	moveCursorRight(columns)
+	p.terminal().MoveCursorRight(columns)
 }
 
 // MoveToNextRow moves cursor to the next row..
 func (p *Printer) MoveToNextRow() {
-	moveCursorToNextRow()
+	p.terminal().MoveCursorToNextRow()
 }
 
 // MoveToRow moves cursor to the specified row
 func (p *Printer) MoveToRow(row int) {
-	moveCursorToRow(row)
+	p.terminal().MoveCursorToRow(row)
 }
 
-func (p *Printer) processString(a ...interface{}) string {
-	out := p.color + p.background
-	if p.bold {
-		out += Bold
-	}
-	if p.underline {
-		out += Underline
-	}
-	if p.reversed {
-		out += Reversed
-	}
-	if p.blinking {
-		out += Blinking
-	}
-	if out == "" {
-		return p.prefix + fmt.Sprint(a...) + p.suffix
+// ClearScreen clears screen.
+func (p *Printer) ClearScreen() {
+	p.terminal().ClearScreen()
+}
+
+// Output methods
+
+// SetOutput sets destination of Print, Printf, Println methods and cursor movements.
+// Passing nil resets output to os.Stdout. Color profile is detected for the new output
+// unless it was set with SetColorProfile.
+func (p *Printer) SetOutput(w io.Writer) {
+	p.out = w
+}
+
+func (p *Printer) output() io.Writer {
+	if p.out == nil {
+		return os.Stdout
 	}
-	out += p.prefix + fmt.Sprint(a...) + p.suffix + Reset
-	return out
+	return p.out
+}
+
+func (p *Printer) terminal() *Terminal {
+	return terminalFor(p.output())
+}
+
+func (p *Printer) processString(w io.Writer, a ...interface{}) string {
+	return p.style.render(p.colorProfile(w), fmt.Sprint(a...))
 }
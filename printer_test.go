@@ -0,0 +1,145 @@
+package termtools
+
+import (
+	"bytes"
+	"testing"
+)
+
+func Test_PrinterOutput(t *testing.T) {
+	var buf bytes.Buffer
+	p, err := NewPrinter(PrinterConfig{Color: "red", Output: &buf})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Print("plain")
+	if buf.String() != "plain" {
+		t.Errorf("buffer is not a terminal, expected no escapes, got %q", buf.String())
+	}
+	buf.Reset()
+	p.SetColorProfile(ANSI16)
+	p.Println("x")
+	p.MoveTo(3, 4)
+	p.PrintAtPosition(1, 2, "y")
+	p.ClearScreen()
+	want := Red + "x" + Reset + "\n" + "\x1b[4;3H" + "\x1b[2;1H" + Red + "y" + Reset + Clear
+	if buf.String() != want {
+		t.Errorf("got %q want %q", buf.String(), want)
+	}
+	buf.Reset()
+	term := NewTerminal(&buf, -1)
+	term.PrintAtPositionAndReturn(5, 6, "z")
+	if want := CursorSave + "\x1b[6;5H" + "z" + CursorRestore; buf.String() != want {
+		t.Errorf("got %q want %q", buf.String(), want)
+	}
+}
+
+func Test_BrightColors(t *testing.T) {
+	var p Printer
+	p.SetColorProfile(ANSI16)
+	p.SetColor("brightred")
+	if got, want := p.Sprint("x"), "\x1b[91mx"+Reset; got != want {
+		t.Errorf("bright color: got %q want %q", got, want)
+	}
+	p.ToggleBold()
+	if got, want := p.Sprint("x"), "\x1b[91m"+Bold+"x"+Reset; got != want {
+		t.Errorf("bright color with bold: got %q want %q", got, want)
+	}
+	p.ToggleBold()
+	p.SetBackground("brightblue")
+	if got, want := p.Sprint("x"), "\x1b[91m\x1b[104mx"+Reset; got != want {
+		t.Errorf("bright background must not make text bold: got %q want %q", got, want)
+	}
+	p.Reset()
+	if got := p.Sprint("x"); got != "x" {
+		t.Errorf("after Reset: got %q", got)
+	}
+	p.SetColor(9)
+	if got, want := p.Sprint("x"), BrightRed+"x"+Reset; got != want {
+		t.Errorf("color 9 in ANSI16 profile: got %q want %q", got, want)
+	}
+
+	SetBoldAsBright(true)
+	defer SetBoldAsBright(false)
+	p.Reset()
+	p.SetColor("brightred")
+	if got, want := p.Sprint("x"), "\x1b[31;1mx"+Reset; got != want {
+		t.Errorf("legacy bright color: got %q want %q", got, want)
+	}
+	if code, _ := GetBackgroundCode("brightgreen"); code != "\x1b[42;1m" {
+		t.Errorf("legacy bright background: got %q", code)
+	}
+	p.SetColor("red")
+	p.ToggleBold()
+	if got, want := p.Sprint("x"), Red+Bold+"x"+Reset; got != want {
+		t.Errorf("legacy mode must not affect base colors: got %q want %q", got, want)
+	}
+}
+
+func Test_ExtendedAttributes(t *testing.T) {
+	p, err := NewPrinter(PrinterConfig{
+		Italic:         true,
+		Faint:          true,
+		Strikethrough:  true,
+		Overline:       true,
+		UnderlineStyle: UnderlineCurly,
+		UnderlineColor: "#ff0000",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.SetColorProfile(TrueColor)
+	want := CurlyUnderline + "\x1b[58;2;255;0;0m" + Faint + Italic + Strikethrough + Overline + "x" + Reset
+	if got := p.Sprint("x"); got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+	p.SetColorProfile(ANSI256)
+	want = CurlyUnderline + "\x1b[58;5;196m" + Faint + Italic + Strikethrough + Overline + "x" + Reset
+	if got := p.Sprint("x"); got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+	p.Reset()
+	p.ToggleHidden()
+	if got := p.Sprint("x"); got != Hidden+"x"+Reset {
+		t.Errorf("hidden: got %q", got)
+	}
+	if _, err := NewPrinter(PrinterConfig{UnderlineColor: "nosuchcolor"}); err != ErrFailedToSetUnderlineColor {
+		t.Errorf("expected ErrFailedToSetUnderlineColor, got %v", err)
+	}
+}
+
+func Test_Gradient(t *testing.T) {
+	red, blue := RGB{255, 0, 0}, RGB{0, 0, 255}
+	if c := GradientColor(0.5, red, RGB{0, 255, 0}, blue); c != (RGB{0, 255, 0}) {
+		t.Errorf("GradientColor(0.5): %v", c)
+	}
+	if c := GradientColor(0.25, red, blue); c != (RGB{191, 0, 64}) {
+		t.Errorf("GradientColor(0.25): %v", c)
+	}
+	var p Printer
+	p.SetColorProfile(TrueColor)
+	want := "\x1b[38;2;255;0;0ma\x1b[38;2;128;0;128mb\x1b[38;2;0;0;255mc" + Reset
+	if got := p.GradientSprint("abc", red, blue); got != want {
+		t.Errorf("GradientSprint: got %q want %q", got, want)
+	}
+	want = "\x1b[38;2;255;0;0mab" + Reset + "\n\x1b[38;2;0;0;255mc" + Reset
+	if got := p.VerticalGradientSprint("ab\nc", red, blue); got != want {
+		t.Errorf("VerticalGradientSprint: got %q want %q", got, want)
+	}
+	p.SetColorProfile(ANSI16)
+	p.ToggleBold()
+	want = BRed + Bold + "ab" + BBlue + Bold + "cd" + Reset
+	if got := p.BackgroundGradientSprint("abcd", RGB{128, 0, 0}, RGB{0, 0, 128}); got != want {
+		t.Errorf("BackgroundGradientSprint: got %q want %q", got, want)
+	}
+	p.SetColorProfile(NoColor)
+	if got := p.RainbowSprint("\x1b[31mabc"); got != "abc" {
+		t.Errorf("RainbowSprint with NoColor: %q", got)
+	}
+	p.SetColorProfile(ANSI256)
+	if got := VisibleWidth(p.RainbowSprint("rainbow")); got != 7 {
+		t.Errorf("RainbowSprint width: %d", got)
+	}
+	if got := p.GradientSprint("abc"); got != Bold+"abc"+Reset {
+		t.Errorf("GradientSprint without stops: %q", got)
+	}
+}
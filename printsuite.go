@@ -3,6 +3,8 @@ package termtools
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 )
 
 var (
@@ -10,8 +12,63 @@ var (
 	ErrFailedToAdd                  = errors.New("error: failed to add printer: name already taken or nil pointer passed")
 	ErrFailedToProcessPrinterConfig = errors.New("error: failed to process PrinterConfig: field Name must not be empty when adding to PrintSuite")
 	ErrEmptyName                    = errors.New("error: printer name may not be empty string")
+	// ErrUnknownParent is returned when PrinterConfig extends printer which does not exist.
+	ErrUnknownParent = errors.New("error: parent printer does not exist")
+	// ErrInheritanceCycle is returned when printer configurations extend each other in a cycle.
+	ErrInheritanceCycle = errors.New("error: printer configurations extend each other in a cycle")
 )
 
+// ConfigError describes failure to process field of PrinterConfig.
+type ConfigError struct {
+	// Entry is the name of printer configuration (may be empty if unknown)
+	Entry string
+	// Field is the name of field which failed
+	Field string
+	// Value is the value of field if available
+	Value interface{}
+	Err   error
+}
+
+func (e *ConfigError) Error() string {
+	where := "field " + e.Field
+	if e.Entry != "" {
+		where = fmt.Sprintf("printer %q %s", e.Entry, where)
+	}
+	if e.Value != nil {
+		where += fmt.Sprintf(" (value %#v)", e.Value)
+	}
+	return fmt.Sprintf("%v: %s", e.Err, where)
+}
+
+// Unwrap returns underlying error.
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// ConfigErrors holds errors of several printer configurations.
+type ConfigErrors []*ConfigError
+
+func (errs ConfigErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// fieldError converts error returned by NewPrinter to *ConfigError.
+func (conf PrinterConfig) fieldError(err error) *ConfigError {
+	switch err {
+	case ErrFailedToSetColor:
+		return &ConfigError{Entry: conf.Name, Field: "Color", Value: conf.Color, Err: ErrUnknownColor}
+	case ErrFailedToSetBackground:
+		return &ConfigError{Entry: conf.Name, Field: "Background", Value: conf.Background, Err: ErrUnknownColor}
+	case ErrFailedToSetUnderlineColor:
+		return &ConfigError{Entry: conf.Name, Field: "UnderlineColor", Value: conf.UnderlineColor, Err: ErrUnknownColor}
+	}
+	return &ConfigError{Entry: conf.Name, Err: err}
+}
+
 // PrintSuite zero ore more configurations of Printer which
 // allows to switch added printer configurations on the fly to use differrent
 // output styles. Printer configurations can be added with AddPrinter() and Configure() methods.
@@ -25,32 +82,137 @@ var (
 type PrintSuite struct {
 	Printer
 	available map[string]*Printer
+	// configs holds configurations passed to Configure, they are
+	// needed to resolve inheritance when parent configuration changes
+	configs map[string]PrinterConfig
 }
 
 // Configure accepts one or more PrinterConfig and adds printers to
 // PrintSuite. If one or more configs fail to process the method will
-// return an error listing names that failed to add.
-// Important: if method encounters empty Name field in PrinterConfig(s), the method will
-// fail with an error and subsequent configurations will not be processed.
+// return ConfigErrors describing each entry and field that failed.
+//
+// Configuration may extend another printer by naming it in Extends field. Parent may be
+// configured in the same call, earlier or later, or added with AddPrinter. When parent
+// configuration is replaced, printers extending it are updated too. Configurations extending
+// printer which does not exist (yet) fail with ErrUnknownParent, configurations extending
+// each other in a cycle fail with ErrInheritanceCycle.
+//
+// Configurations are added only if all of them succeed. If any of them fails (or has empty
+// Name field, in which case ErrFailedToProcessPrinterConfig is returned) PrintSuite is left unchanged.
 func (suite *PrintSuite) Configure(configs ...PrinterConfig) error {
 	suite.ensureMapExists()
-	failing := ""
 	for _, conf := range configs {
 		if conf.Name == "" {
 			return ErrFailedToProcessPrinterConfig
 		}
-		if p, err := NewPrinter(conf); err == nil {
-			suite.available[conf.Name] = p
-		} else {
-			failing += conf.Name + " "
+	}
+	// previous holds replaced configurations to restore them if configuration fails
+	previous := make(map[string]*PrinterConfig)
+	var names []string
+	for _, conf := range configs {
+		if _, ok := previous[conf.Name]; !ok {
+			if old, ok := suite.configs[conf.Name]; ok {
+				previous[conf.Name] = &old
+			} else {
+				previous[conf.Name] = nil
+			}
 		}
+		suite.configs[conf.Name] = conf
+		names = append(names, conf.Name)
 	}
-	if failing != "" {
-		return fmt.Errorf("error: failed to add the following names: %v", failing)
+	var (
+		failing ConfigErrors
+		built   = make(map[string]*Printer)
+	)
+	for _, name := range suite.withDescendants(names) {
+		if _, err := suite.build(name, built, nil); err != nil {
+			failing = append(failing, err)
+		}
+	}
+	if failing != nil {
+		for name, old := range previous {
+			if old != nil {
+				suite.configs[name] = *old
+			} else {
+				delete(suite.configs, name)
+			}
+		}
+		return failing
+	}
+	for name, p := range built {
+		suite.available[name] = p
 	}
 	return nil
 }
 
+// withDescendants returns names followed by names of all configurations
+// extending them directly or indirectly. Children of each configuration are sorted
+// by name, so that configurations are built (and fail) in the same order every time.
+func (suite *PrintSuite) withDescendants(names []string) []string {
+	seen := make(map[string]bool)
+	for _, name := range names {
+		seen[name] = true
+	}
+	for i := 0; i < len(names); i++ {
+		var children []string
+		for child, conf := range suite.configs {
+			if conf.Extends == names[i] && !seen[child] {
+				seen[child] = true
+				children = append(children, child)
+			}
+		}
+		sort.Strings(children)
+		names = append(names, children...)
+	}
+	return names
+}
+
+// build creates printer from stored configuration resolving its parents. Printers
+// built during current Configure call are kept in built, chain holds names of
+// configurations being resolved to detect cycles.
+func (suite *PrintSuite) build(name string, built map[string]*Printer, chain []string) (*Printer, *ConfigError) {
+	if p, ok := built[name]; ok {
+		return p, nil
+	}
+	conf := suite.configs[name]
+	p, err := NewPrinter(conf)
+	if err != nil {
+		return nil, conf.fieldError(err)
+	}
+	if conf.Extends != "" {
+		chain = append(chain, name)
+		parent, err := suite.parent(conf, built, chain)
+		if err != nil {
+			return nil, err
+		}
+		p.style = p.style.Inherit(parent.style)
+		if p.out == nil {
+			p.out = parent.out
+		}
+	}
+	built[name] = p
+	return p, nil
+}
+
+func (suite *PrintSuite) parent(conf PrinterConfig, built map[string]*Printer, chain []string) (*Printer, *ConfigError) {
+	for _, name := range chain {
+		if name == conf.Extends {
+			return nil, &ConfigError{Entry: conf.Name, Field: "Extends", Value: strings.Join(append(chain, name), " -> "), Err: ErrInheritanceCycle}
+		}
+	}
+	if _, ok := suite.configs[conf.Extends]; ok {
+		p, err := suite.build(conf.Extends, built, chain)
+		if err != nil {
+			return nil, &ConfigError{Entry: conf.Name, Field: "Extends", Value: conf.Extends, Err: err}
+		}
+		return p, nil
+	}
+	if p, ok := suite.available[conf.Extends]; ok {
+		return p, nil
+	}
+	return nil, &ConfigError{Entry: conf.Name, Field: "Extends", Value: conf.Extends, Err: ErrUnknownParent}
+}
+
 // Use returns instance of printer with requested printername. If printername is invalid
 // (no printer with such name has been added or name is empty string) a default Printer instance is returned.
 func (suite *PrintSuite) Use(printername string) *Printer {
@@ -109,4 +271,7 @@ func (suite *PrintSuite) ensureMapExists() {
 	if suite.available == nil {
 		suite.available = make(map[string]*Printer)
 	}
+	if suite.configs == nil {
+		suite.configs = make(map[string]PrinterConfig)
+	}
 }
@@ -0,0 +1,155 @@
+package termtools
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// ColorProfile describes set of colors supported by terminal.
+type ColorProfile int
+
+const (
+	// NoColor profile disables all escape sequences.
+	NoColor ColorProfile = iota
+	// ANSI16 profile supports 16 base colors.
+	ANSI16
+	// ANSI256 profile supports 256 colors palette.
+	ANSI256
+	// TrueColor profile supports 24-bit colors.
+	TrueColor
+)
+
+var profileNames = map[ColorProfile]string{
+	NoColor:   "NoColor",
+	ANSI16:    "ANSI16",
+	ANSI256:   "ANSI256",
+	TrueColor: "TrueColor"}
+
+// String implements fmt.Stringer.
+func (profile ColorProfile) String() string {
+	if name, ok := profileNames[profile]; ok {
+		return name
+	}
+	return "ColorProfile(unknown)"
+}
+
+// DetectColorProfile returns color profile of standard output. Profile is derived from TERM and COLORTERM
+// environment variables and is subject to color policy:
+//
+// NoColor is returned if NO_COLOR environment variable is set, if CLICOLOR is "0" or
+// if standard output is not a terminal. Setting FORCE_COLOR or CLICOLOR_FORCE to a value other than "0"
+// forces colors even if output is not a terminal (FORCE_COLOR values "2" and "3" request
+// ANSI256 and TrueColor profiles accordingly). NO_COLOR takes precedence over forcing variables.
+//
+// Color mode set with SetColorMode (or with flag returned by ColorFlag) overrides
+// environment: ColorNever always results in NoColor and ColorAlways forces colors.
+func DetectColorProfile() ColorProfile {
+	return detectColorProfile(os.Stdout)
+}
+
+// detectColorProfile applies color policy to w.
+func detectColorProfile(w io.Writer) ColorProfile {
+	return colorPolicy(GetColorMode(), os.Getenv, isTerminalWriter(w))
+}
+
+func colorPolicy(mode ColorMode, getenv func(string) string, tty bool) ColorProfile {
+	switch mode {
+	case ColorNever:
+		return NoColor
+	case ColorAlways:
+		return forcedColorProfile(getenv, ANSI16)
+	}
+	if getenv("NO_COLOR") != "" {
+		return NoColor
+	}
+	switch force := strings.ToLower(getenv("FORCE_COLOR")); force {
+	case "":
+	case "0", "false":
+		return NoColor
+	case "2":
+		return forcedColorProfile(getenv, ANSI256)
+	case "3":
+		return forcedColorProfile(getenv, TrueColor)
+	default:
+		return forcedColorProfile(getenv, ANSI16)
+	}
+	if force := getenv("CLICOLOR_FORCE"); force != "" && force != "0" {
+		return forcedColorProfile(getenv, ANSI16)
+	}
+	if getenv("CLICOLOR") == "0" || !tty {
+		return NoColor
+	}
+	return envColorProfile(getenv)
+}
+
+// forcedColorProfile returns profile derived from environment but not lower than min.
+func forcedColorProfile(getenv func(string) string, min ColorProfile) ColorProfile {
+	if profile := envColorProfile(getenv); profile > min {
+		return profile
+	}
+	return min
+}
+
+func envColorProfile(getenv func(string) string) ColorProfile {
+	switch strings.ToLower(getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return TrueColor
+	}
+	term := strings.ToLower(getenv("TERM"))
+	switch {
+	case term == "dumb":
+		return NoColor
+	case strings.Contains(term, "truecolor"), strings.Contains(term, "24bit"), strings.Contains(term, "direct"),
+		strings.HasPrefix(term, "xterm-kitty"), strings.HasPrefix(term, "alacritty"), strings.HasPrefix(term, "wezterm"),
+		strings.HasPrefix(term, "foot"):
+		return TrueColor
+	case strings.Contains(term, "256color"):
+		return ANSI256
+	}
+	return ANSI16
+}
+
+func isTerminalWriter(w io.Writer) bool {
+	if f, ok := w.(*os.File); ok {
+		return isTerminal(int(f.Fd()))
+	}
+	return false
+}
+
+func isTerminal(fd int) bool {
+	_, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	return err == nil
+}
+
+// NearestANSI256 returns ID of color from 256 colors palette which is closest to c.
+// Only 6x6x6 color cube and grayscale ramp (IDs 16 to 255) are considered because
+// terminals often redefine 16 base colors.
+func NearestANSI256(c RGB) int {
+	return nearestColor(c, xtermColors[16:]) + 16
+}
+
+// NearestANSI16 returns ID (in range [0;15]) of base color which is closest to c.
+func NearestANSI16(c RGB) int {
+	return nearestColor(c, xtermColors[:16])
+}
+
+func nearestColor(c RGB, colors []RGB) int {
+	best, bestDist := 0, -1
+	for i, candidate := range colors {
+		if d := colorDistance(c, candidate); bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
+// colorDistance returns weighted squared distance between two colors
+// approximating perceived difference ("redmean" formula).
+func colorDistance(a, b RGB) int {
+	rmean := (int(a.R) + int(b.R)) / 2
+	dr, dg, db := int(a.R)-int(b.R), int(a.G)-int(b.G), int(a.B)-int(b.B)
+	return ((512+rmean)*dr*dr)>>8 + 4*dg*dg + ((767-rmean)*db*db)>>8
+}
@@ -0,0 +1,135 @@
+package termtools
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// ErrNotTerminal is returned when file descriptor passed to MakeRaw, MakeCbreak and alike is not a terminal.
+var ErrNotTerminal = errors.New("error: file descriptor is not a terminal")
+
+// State holds terminal settings which may be restored later.
+type State struct {
+	fd      int
+	termios unix.Termios
+}
+
+// GetState returns current settings of terminal fd.
+func GetState(fd int) (*State, error) {
+	termios, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return nil, ErrNotTerminal
+	}
+	return &State{fd: fd, termios: *termios}, nil
+}
+
+// Restore sets terminal settings back to the saved state.
+func (s *State) Restore() error {
+	return unix.IoctlSetTermios(s.fd, ioctlSetTermios, &s.termios)
+}
+
+// MakeRaw puts terminal fd into raw mode and returns its previous state. In raw mode
+// input is available byte by byte without line editing and echo, key combinations like Ctrl+C
+// do not send signals and output is not processed, so "\n" does not return cursor to the
+// beginning of line (print "\r\n" instead).
+func MakeRaw(fd int) (*State, error) {
+	return setMode(fd, func(t *unix.Termios) {
+		t.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+		t.Oflag &^= unix.OPOST
+		t.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+		t.Cflag &^= unix.CSIZE | unix.PARENB
+		t.Cflag |= unix.CS8
+		t.Cc[unix.VMIN], t.Cc[unix.VTIME] = 1, 0
+	})
+}
+
+// MakeCbreak puts terminal fd into cbreak mode and returns its previous state. In cbreak mode
+// input is available byte by byte without line editing and echo, but unlike raw mode
+// Ctrl+C and alike still send signals and output is processed as usual.
+func MakeCbreak(fd int) (*State, error) {
+	return setMode(fd, func(t *unix.Termios) {
+		t.Lflag &^= unix.ECHO | unix.ICANON
+		t.Cc[unix.VMIN], t.Cc[unix.VTIME] = 1, 0
+	})
+}
+
+// SetEcho switches echo of typed characters in terminal fd on or off leaving other
+// settings intact. This is useful to read passwords.
+func SetEcho(fd int, on bool) error {
+	_, err := setMode(fd, func(t *unix.Termios) {
+		if on {
+			t.Lflag |= unix.ECHO
+		} else {
+			t.Lflag &^= unix.ECHO
+		}
+	})
+	return err
+}
+
+// setMode modifies settings of terminal fd with change and returns previous state.
+func setMode(fd int, change func(*unix.Termios)) (*State, error) {
+	state, err := GetState(fd)
+	if err != nil {
+		return nil, err
+	}
+	termios := state.termios
+	change(&termios)
+	if err := unix.IoctlSetTermios(fd, ioctlSetTermios, &termios); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// RestoreOnSignal restores the state when process receives SIGINT or SIGTERM and then
+// lets the signal terminate process as usual. Call returned function to stop watching for signals,
+// it is safe to call it more than once.
+func (s *State) RestoreOnSignal() (stop func()) {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-signals:
+			s.Restore()
+			signal.Reset(sig)
+			unix.Kill(os.Getpid(), sig.(syscall.Signal))
+		case <-done:
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+	}
+}
+
+// WithRawMode puts terminal fd into raw mode (see MakeRaw), calls f and restores the terminal.
+// The terminal is restored when f returns or panics and when process receives SIGINT or SIGTERM.
+// Error returned by f is passed to the caller.
+func WithRawMode(fd int, f func() error) error {
+	return withMode(fd, MakeRaw, f)
+}
+
+// WithCbreakMode puts terminal fd into cbreak mode (see MakeCbreak), calls f and restores the terminal.
+// See WithRawMode.
+func WithCbreakMode(fd int, f func() error) error {
+	return withMode(fd, MakeCbreak, f)
+}
+
+func withMode(fd int, mode func(int) (*State, error), f func() error) error {
+	state, err := mode(fd)
+	if err != nil {
+		return err
+	}
+	defer state.Restore()
+	stop := state.RestoreOnSignal()
+	defer stop()
+	return f()
+}
@@ -0,0 +1,57 @@
+package termtools
+
+import (
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+func Test_RawMode(t *testing.T) {
+	_, slave := openPty(t)
+	fd := int(slave.Fd())
+	lflag := func() uint64 {
+		termios, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return uint64(termios.Lflag)
+	}
+	initial := lflag()
+	if initial&unix.ECHO == 0 || initial&unix.ICANON == 0 {
+		t.Fatalf("unexpected initial flags %x", initial)
+	}
+	state, err := MakeRaw(fd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flags := lflag(); flags&(unix.ECHO|unix.ICANON|unix.ISIG) != 0 {
+		t.Errorf("MakeRaw left flags %x", flags)
+	}
+	if err := state.Restore(); err != nil || lflag() != initial {
+		t.Errorf("Restore: %v, flags %x", err, lflag())
+	}
+	state, _ = MakeCbreak(fd)
+	if flags := lflag(); flags&(unix.ECHO|unix.ICANON) != 0 || flags&unix.ISIG == 0 {
+		t.Errorf("MakeCbreak flags %x", flags)
+	}
+	state.Restore()
+	if err := SetEcho(fd, false); err != nil || lflag() != initial&^unix.ECHO {
+		t.Errorf("SetEcho(false): %v, flags %x", err, lflag())
+	}
+	SetEcho(fd, true)
+	func() {
+		defer func() { recover() }()
+		WithRawMode(fd, func() error {
+			panic("oops")
+		})
+	}()
+	if lflag() != initial {
+		t.Errorf("terminal was not restored after panic: flags %x", lflag())
+	}
+	stop := state.RestoreOnSignal()
+	stop()
+	stop()
+	if _, err := MakeRaw(-1); err != ErrNotTerminal {
+		t.Errorf("MakeRaw(-1): %v", err)
+	}
+}
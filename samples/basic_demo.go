@@ -26,7 +26,7 @@ func main() {
 	// Simplest use is to call ColorSprint (same signature as in fmt.Sprint, but the
 	// first argument is the name of the color).
 	tt.ClearScreen()                                        // Clears Screen
-	tt.MoveCursorTo(0, 0)                                   // Moves cursor to top left
+	tt.MoveCursorTo(1, 1)                                   // Moves cursor to top left
 	mystring := tt.Csprint("red", "This will print in red") // Colorizes input string
 	fmt.Println(mystring)
 
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/dmfed/termtools"
+)
+
+const banner = ` _                       _              _
+| |_ ___ _ __ _ __ ___  | |_ ___   ___ | |___
+| __/ _ \ '__| '_ ` + "`" + ` _ \ | __/ _ \ / _ \| / __|
+| ||  __/ |  | | | | | || || (_) | (_) | \__ \
+ \__\___|_|  |_| |_| |_| \__\___/ \___/|_|___/`
+
+func main() {
+	var p termtools.Printer
+	p.Println(p.GradientSprint(banner, termtools.RGB{R: 255, G: 95, B: 0}, termtools.RGB{R: 175, G: 0, B: 255}))
+	p.Println(p.VerticalGradientSprint(banner, termtools.RGB{R: 0, G: 215, B: 255}, termtools.RGB{R: 0, G: 95, B: 0}))
+	p.Println(p.RainbowSprint(banner))
+	p.ToggleAutoForeground()
+	p.Println(p.BackgroundGradientSprint(strings.Repeat(" ", 20)+"progress"+strings.Repeat(" ", 20),
+		termtools.RGB{R: 0, G: 0, B: 95}, termtools.RGB{R: 255, G: 255, B: 175}))
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dmfed/termtools"
+)
+
+// Prints pressed keys, mouse events, pasted text and focus changes until q or Ctrl+C is pressed.
+func main() {
+	fd := int(os.Stdin.Fd())
+	err := termtools.WithRawMode(fd, func() error {
+		fmt.Print("Press keys or click (q or Ctrl+C to quit)\r\n")
+		termtools.EnableMouse(termtools.MouseButtonEvent | termtools.MouseSGR)
+		defer termtools.DisableMouse()
+		termtools.EnableBracketedPaste()
+		defer termtools.DisableBracketedPaste()
+		termtools.EnableFocusReporting()
+		defer termtools.DisableFocusReporting()
+		defer termtools.EnableKeyboardFlags(termtools.KeyboardDisambiguate|termtools.KeyboardReportEvents, 100*time.Millisecond)()
+		keys := termtools.NewKeyReader(os.Stdin)
+		for {
+			ev, err := keys.ReadEvent()
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%v\r\n", ev)
+			if ev.String() == "q" || ev.String() == "ctrl+c" {
+				return nil
+			}
+		}
+	})
+	if err != nil {
+		fmt.Println(err)
+	}
+}
@@ -6,6 +6,19 @@ import (
 	"github.com/dmfed/termtools"
 )
 
+// PrintRGB outputs IDs of 256 colors with their RGB values and names of base colors
+func PrintRGB() {
+	palette := termtools.XtermPalette()
+	p := termtools.Printer{}
+	for id, rgb := range palette {
+		p.SetBackground(id)
+		p.Print("   ")
+		p.Reset()
+		name, _ := termtools.ColorName(id)
+		p.Printf(" %3d %s %s\n", id, rgb.Hex(), name)
+	}
+}
+
 // PrintPalette outputs 256 colors with their IDs
 func PrintPalette(width int, combine bool) {
 	x, _, _ := termtools.GetTermSize()
@@ -45,7 +58,12 @@ func main() {
 	var (
 		width   = flag.Int("w", 20, "How many color samples to print on a line?")
 		combine = flag.Bool("c", false, "Print color codes on color samples")
+		rgb     = flag.Bool("rgb", false, "Print RGB values of colors")
 	)
 	flag.Parse()
+	if *rgb {
+		PrintRGB()
+		return
+	}
 	PrintPalette(*width, *combine)
 }
@@ -13,7 +13,7 @@ func PrintCentered(s string) {
 	termtools.ClearScreen()
 	fmt.Print(strings.Repeat("\n", y))
 	s = termtools.Csprint("blue", s)
-	termtools.PrintAtPositionAndReturn(x/2-len(s)/2, y/2, s)
+	termtools.PrintAtPositionAndReturn(x/2-termtools.VisibleWidth(s)/2, y/2, s)
 }
 
 func main() {
@@ -0,0 +1,127 @@
+package termtools
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// ErrUnknownColormap is returned when creating Scale with unknown colormap name.
+var ErrUnknownColormap = errors.New("error: unknown colormap")
+
+// colormaps hold colors of named colormaps from the lowest value to the highest.
+var colormaps = map[string][]RGB{
+	"viridis": hexColors("#440154", "#482878", "#3e4989", "#31688e", "#26828e",
+		"#1f9e89", "#35b779", "#6ece58", "#b5de2b", "#fde725"),
+	"magma": hexColors("#000004", "#180f3d", "#440f76", "#721f81", "#9e2f7f",
+		"#cd4071", "#f1605d", "#fd9668", "#feca8d", "#fcfdbf"),
+	"red-yellow-green": hexColors("#a50026", "#d73027", "#f46d43", "#fdae61", "#fee08b", "#ffffbf",
+		"#d9ef8b", "#a6d96a", "#66bd63", "#1a9850", "#006837"),
+	"red-blue": hexColors("#67001f", "#b2182b", "#d6604d", "#f4a582", "#fddbc7", "#f7f7f7",
+		"#d1e5f0", "#92c5de", "#4393c3", "#2166ac", "#053061"),
+	"grayscale": hexColors("#000000", "#ffffff"),
+}
+
+func hexColors(hex ...string) (colors []RGB) {
+	for _, h := range hex {
+		c, err := ParseHex(h)
+		if err != nil {
+			panic(err)
+		}
+		colors = append(colors, c)
+	}
+	return
+}
+
+// Colormaps returns sorted names of colormaps accepted by NewScale.
+func Colormaps() []string {
+	var names []string
+	for name := range colormaps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Scale maps numeric values to colors of colormap. It is useful to color values by
+// magnitude, for example in heatmaps or tables of latencies.
+type Scale struct {
+	colors        []RGB
+	min, mid, max float64
+	diverging     bool
+}
+
+// NewScale returns Scale mapping values from min to max to colors of named colormap:
+// "viridis", "magma", "red-yellow-green", "red-blue" or "grayscale". Values outside of
+// range get colors of the nearest end. If min is greater than max the colormap is reversed,
+// for example NewScale("red-yellow-green", 500, 0) colors low latencies green and high ones red.
+func NewScale(colormap string, min, max float64) (*Scale, error) {
+	colors, ok := colormaps[colormap]
+	if !ok {
+		return nil, ErrUnknownColormap
+	}
+	return &Scale{colors: colors, min: min, max: max}, nil
+}
+
+// NewDivergingScale returns Scale mapping values from min to mid to the first half of colormap
+// and values from mid to max to the second half. The middle color of colormap
+// marks mid value. Diverging colormaps like "red-blue" or "red-yellow-green" fit best.
+func NewDivergingScale(colormap string, min, mid, max float64) (*Scale, error) {
+	s, err := NewScale(colormap, min, max)
+	if err != nil {
+		return nil, err
+	}
+	s.mid, s.diverging = mid, true
+	return s, nil
+}
+
+// position returns position of value v in colormap in range [0;1].
+func (s *Scale) position(v float64) float64 {
+	if math.IsNaN(v) {
+		return 0
+	}
+	if !s.diverging {
+		return clampUnit(ratio(v-s.min, s.max-s.min))
+	}
+	if (v-s.mid)*(s.min-s.mid) > 0 {
+		// v is on the same side of mid as min
+		return 0.5 - clampUnit(ratio(v-s.mid, s.min-s.mid))/2
+	}
+	return 0.5 + clampUnit(ratio(v-s.mid, s.max-s.mid))/2
+}
+
+func ratio(a, b float64) float64 {
+	if b == 0 {
+		return 0
+	}
+	return a / b
+}
+
+// Color returns color for value v.
+func (s *Scale) Color(v float64) RGB {
+	return GradientColor(s.position(v), s.colors...)
+}
+
+// Code returns escape sequence setting text color for value v. The code is
+// the same as GetColorCode returns for the color.
+func (s *Scale) Code(v float64) string {
+	return colorValue{kind: colorTrue, rgb: s.Color(v)}.code(TrueColor, layerForeground)
+}
+
+// BackgroundCode returns escape sequence setting background color for value v. The code is
+// the same as GetBackgroundCode returns for the color.
+func (s *Scale) BackgroundCode(v float64) string {
+	return colorValue{kind: colorTrue, rgb: s.Color(v)}.code(TrueColor, layerBackground)
+}
+
+// Printer returns Printer with text color for value v. Unlike codes returned by Code,
+// output of Printer is downsampled to color profile of terminal.
+func (s *Scale) Printer(v float64) *Printer {
+	return &Printer{style: Style{}.Foreground(s.Color(v))}
+}
+
+// BackgroundPrinter returns Printer with background color for value v and readable
+// text color (see ReadableForeground).
+func (s *Scale) BackgroundPrinter(v float64) *Printer {
+	return &Printer{style: Style{}.Background(s.Color(v)).AutoForeground(true)}
+}
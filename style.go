@@ -0,0 +1,280 @@
+package termtools
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Attribute is a text attribute (mode) which can be set in Style.
+type Attribute uint32
+
+const (
+	AttrBold Attribute = 1 << iota
+	AttrUnderline
+	AttrReversed
+	AttrBlinking
+	AttrFaint
+	AttrItalic
+	AttrHidden
+	AttrStrikethrough
+	AttrOverline
+)
+
+// Bits of Style.set field marking non-attribute fields as set.
+const (
+	setForeground Attribute = 1 << (iota + 16)
+	setBackground
+	setUnderlineColor
+	setPrefix
+	setSuffix
+	setAutoForeground
+)
+
+// attributeCodes lists attributes in order of output. Underline is
+// handled separately because of underline style and color.
+var attributeCodes = []struct {
+	attr Attribute
+	code string
+}{
+	{AttrBold, Bold}, {AttrUnderline, ""}, {AttrReversed, Reversed}, {AttrBlinking, Blinking},
+	{AttrFaint, Faint}, {AttrItalic, Italic}, {AttrHidden, Hidden}, {AttrStrikethrough, Strikethrough},
+	{AttrOverline, Overline}}
+
+// Style is an immutable set of color and text attributes with optional prefix and suffix.
+// Methods of Style never modify it but return modified copy, so Style may be safely
+// shared between goroutines and used as a base for other styles:
+//
+//	base := termtools.Style{}.Foreground("red").Bold()
+//	warning := base.Foreground("yellow")
+//	fmt.Println(warning.Render("careful!"))
+//
+// Style keeps track of which fields were set. Fields which were not set may be
+// inherited from another Style with Inherit and Merge methods.
+// The zero value is a Style with nothing set which renders its input unchanged.
+type Style struct {
+	fg, bg, ul     colorValue
+	underlineStyle UnderlineStyle
+	attrs          Attribute // attributes switched on
+	set            Attribute // attributes and fields which were set
+	autoForeground bool
+	prefix, suffix string
+	err            error
+}
+
+// Foreground returns copy of Style with foreground (text) color set. Argument color accepts
+// the same values as Printer SetColor method. If color is invalid the style is returned unchanged
+// with error available from Err method.
+func (s Style) Foreground(color interface{}) Style {
+	c, err := parseColor(color)
+	if err != nil {
+		return s.withError(ErrFailedToSetColor)
+	}
+	s.fg = c
+	s.set |= setForeground
+	return s
+}
+
+// Background returns copy of Style with background color set. See Foreground.
+func (s Style) Background(color interface{}) Style {
+	c, err := parseColor(color)
+	if err != nil {
+		return s.withError(ErrFailedToSetBackground)
+	}
+	s.bg = c
+	s.set |= setBackground
+	return s
+}
+
+// UnderlineColor returns copy of Style with color of underline set. See Foreground.
+func (s Style) UnderlineColor(color interface{}) Style {
+	c, err := parseColor(color)
+	if err != nil {
+		return s.withError(ErrFailedToSetUnderlineColor)
+	}
+	s.ul = c
+	s.set |= setUnderlineColor
+	return s
+}
+
+// UnderlineStyle returns copy of Style with underline switched on in requested style.
+func (s Style) UnderlineStyle(style UnderlineStyle) Style {
+	s.underlineStyle = style
+	return s.Set(AttrUnderline, true)
+}
+
+// Set returns copy of Style with attribute(s) explicitly switched on or off. Attributes explicitly
+// switched off are not inherited from parent style. Several attributes may be combined with "|".
+func (s Style) Set(attr Attribute, on bool) Style {
+	attr &= allAttributes
+	if on {
+		s.attrs |= attr
+	} else {
+		s.attrs &^= attr
+	}
+	s.set |= attr
+	return s
+}
+
+// Has reports whether attribute is switched on.
+func (s Style) Has(attr Attribute) bool {
+	return s.attrs&attr == attr
+}
+
+const allAttributes = AttrBold | AttrUnderline | AttrReversed | AttrBlinking | AttrFaint |
+	AttrItalic | AttrHidden | AttrStrikethrough | AttrOverline
+
+// Bold returns copy of Style with bold attribute on.
+func (s Style) Bold() Style { return s.Set(AttrBold, true) }
+
+// Faint returns copy of Style with faint (dim) attribute on.
+func (s Style) Faint() Style { return s.Set(AttrFaint, true) }
+
+// Italic returns copy of Style with italic attribute on.
+func (s Style) Italic() Style { return s.Set(AttrItalic, true) }
+
+// Underline returns copy of Style with underline attribute on.
+func (s Style) Underline() Style { return s.Set(AttrUnderline, true) }
+
+// Blinking returns copy of Style with blinking attribute on.
+func (s Style) Blinking() Style { return s.Set(AttrBlinking, true) }
+
+// Reversed returns copy of Style with reversed attribute on.
+func (s Style) Reversed() Style { return s.Set(AttrReversed, true) }
+
+// Hidden returns copy of Style with hidden (concealed) attribute on.
+func (s Style) Hidden() Style { return s.Set(AttrHidden, true) }
+
+// Strikethrough returns copy of Style with strikethrough attribute on.
+func (s Style) Strikethrough() Style { return s.Set(AttrStrikethrough, true) }
+
+// Overline returns copy of Style with overline attribute on.
+func (s Style) Overline() Style { return s.Set(AttrOverline, true) }
+
+// AutoForeground returns copy of Style which picks readable foreground (black or white, see
+// ReadableForeground) for its background if foreground color is not set.
+func (s Style) AutoForeground(on bool) Style {
+	s.autoForeground = on
+	s.set |= setAutoForeground
+	return s
+}
+
+// Prefix returns copy of Style which preceeds output with prefix.
+func (s Style) Prefix(prefix string) Style {
+	s.prefix = prefix
+	s.set |= setPrefix
+	return s
+}
+
+// Suffix returns copy of Style which ends output with suffix.
+func (s Style) Suffix(suffix string) Style {
+	s.suffix = suffix
+	s.set |= setSuffix
+	return s
+}
+
+// Inherit returns copy of Style where fields and attributes which were not set
+// are taken from parent.
+func (s Style) Inherit(parent Style) Style {
+	if s.set&setForeground == 0 {
+		s.fg = parent.fg
+	}
+	if s.set&setBackground == 0 {
+		s.bg = parent.bg
+	}
+	if s.set&setUnderlineColor == 0 {
+		s.ul = parent.ul
+	}
+	if s.set&AttrUnderline == 0 {
+		s.underlineStyle = parent.underlineStyle
+	}
+	if s.set&setPrefix == 0 {
+		s.prefix = parent.prefix
+	}
+	if s.set&setSuffix == 0 {
+		s.suffix = parent.suffix
+	}
+	if s.set&setAutoForeground == 0 {
+		s.autoForeground = parent.autoForeground
+	}
+	s.attrs |= parent.attrs &^ s.set
+	s.set |= parent.set
+	if s.err == nil {
+		s.err = parent.err
+	}
+	return s
+}
+
+// Merge returns copy of Style with fields and attributes which were set in other
+// overriding those of s. It is the same as other.Inherit(s).
+func (s Style) Merge(other Style) Style {
+	return other.Inherit(s)
+}
+
+// Err returns the first error encountered while building Style (for example
+// invalid color passed to Foreground) or nil.
+func (s Style) Err() error {
+	return s.err
+}
+
+func (s Style) withError(err error) Style {
+	if s.err == nil {
+		s.err = err
+	}
+	return s
+}
+
+// Render formats its operands as fmt.Sprint and applies the Style. Colors are
+// downsampled to profile detected for standard output.
+func (s Style) Render(a ...interface{}) string {
+	return s.render(DetectColorProfile(), fmt.Sprint(a...))
+}
+
+// Printer returns new Printer with the Style.
+func (s Style) Printer() *Printer {
+	return &Printer{style: s}
+}
+
+// String implements fmt.Stringer. It returns escape sequences setting the
+// style (without prefix and suffix) for TrueColor profile.
+func (s Style) String() string {
+	return s.codes(TrueColor)
+}
+
+func (s Style) render(profile ColorProfile, text string) string {
+	if profile == NoColor {
+		return s.prefix + text + s.suffix
+	}
+	codes := s.codes(profile)
+	if codes == "" {
+		return s.prefix + text + s.suffix
+	}
+	return codes + s.prefix + text + s.suffix + Reset
+}
+
+// codes returns escape sequences setting the style.
+func (s Style) codes(profile ColorProfile) string {
+	if profile == NoColor {
+		return ""
+	}
+	var b strings.Builder
+	fg := s.fg
+	if !fg.isSet() && s.autoForeground {
+		if bg, ok := s.bg.rgbValue(profile); ok {
+			fg = colorValue{kind: colorTrue, rgb: ReadableForeground(bg)}
+		}
+	}
+	b.WriteString(fg.code(profile, layerForeground))
+	b.WriteString(s.bg.code(profile, layerBackground))
+	for _, attr := range attributeCodes {
+		if !s.Has(attr.attr) {
+			continue
+		}
+		if attr.attr == AttrUnderline {
+			b.WriteString(s.underlineStyle.code())
+			b.WriteString(s.ul.code(profile, layerUnderline))
+			continue
+		}
+		b.WriteString(attr.code)
+	}
+	return b.String()
+}
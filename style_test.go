@@ -0,0 +1,52 @@
+package termtools
+
+import (
+	"sync"
+	"testing"
+)
+
+func Test_Style(t *testing.T) {
+	base := Style{}.Foreground("red").Bold().Prefix("> ")
+	warning := Style{}.Foreground("yellow").Set(AttrBold, false).Underline().Inherit(base)
+	if got, want := warning.render(ANSI16, "x"), Yellow+Underline+"> x"+Reset; got != want {
+		t.Errorf("Inherit: got %q want %q", got, want)
+	}
+	if got, want := base.Merge(Style{}.Italic()).render(ANSI16, "x"), Red+Bold+Italic+"> x"+Reset; got != want {
+		t.Errorf("Merge: got %q want %q", got, want)
+	}
+	if got, want := base.render(ANSI16, "x"), Red+Bold+"> x"+Reset; got != want {
+		t.Errorf("base style changed: got %q want %q", got, want)
+	}
+	if s := base.Foreground("nosuchcolor"); s.Err() != ErrFailedToSetColor || s.render(ANSI16, "x") != base.render(ANSI16, "x") {
+		t.Errorf("invalid color must leave style unchanged and set error, got %v", s.Err())
+	}
+	if got := (Style{}).render(TrueColor, "x"); got != "x" {
+		t.Errorf("zero Style: got %q", got)
+	}
+
+	conf := PrinterConfig{Color: "green", Background: 17, Italic: true, Suffix: "!"}
+	p, _ := NewPrinter(conf)
+	p.SetColorProfile(ANSI256)
+	if got, want := p.Sprint("x"), conf.Style().render(ANSI256, "x"); got != want {
+		t.Errorf("Printer and PrinterConfig style differ: %q and %q", got, want)
+	}
+	q := base.Printer()
+	q.SetColorProfile(ANSI16)
+	q.ToggleBold()
+	if got, want := q.Sprint("x"), Red+"> x"+Reset; got != want || !base.Has(AttrBold) {
+		t.Errorf("Printer from Style: got %q want %q", got, want)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			s := base.Background(id)
+			if !s.Has(AttrBold) || s.render(ANSI256, "") == base.render(ANSI256, "") {
+				t.Error("derived style is wrong")
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,207 @@
+package termtools
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// Terminal wraps output destination and file descriptor of terminal. It implements
+// functions to control cursor position, clear screen and print at position
+// writing to the wrapped io.Writer. The file descriptor is used to get terminal size.
+//
+// Package level functions like MoveCursorTo or ClearScreen write to standard output.
+type Terminal struct {
+	out io.Writer
+	fd  int
+	// sizeFromStdin makes Size fall back to standard input if fd is not a terminal,
+	// so that size is known when standard output is redirected.
+	sizeFromStdin bool
+}
+
+// NewTerminal returns Terminal writing to w. Argument fd is the file descriptor
+// of terminal which is used to find out its size. If fd is negative size of terminal is
+// considered unknown and cursor movements are not checked against bounds of the screen.
+// This is useful to write output to a buffer.
+func NewTerminal(w io.Writer, fd int) *Terminal {
+	return &Terminal{out: w, fd: fd}
+}
+
+// stdTerminal is used by package level functions.
+var stdTerminal = &Terminal{out: os.Stdout, fd: int(os.Stdout.Fd()), sizeFromStdin: true}
+
+// terminalFor returns Terminal writing to w. If w is *os.File its file
+// descriptor is used.
+func terminalFor(w io.Writer) *Terminal {
+	if w == os.Stdout {
+		return stdTerminal
+	}
+	if f, ok := w.(*os.File); ok {
+		return NewTerminal(f, int(f.Fd()))
+	}
+	return NewTerminal(w, -1)
+}
+
+// Write implements io.Writer.
+func (t *Terminal) Write(b []byte) (n int, err error) {
+	return t.out.Write(b)
+}
+
+// Size returns current terminal size (number of columns and rows).
+// It returns -1, -1 and an error if size is unknown.
+func (t *Terminal) Size() (columns int, rows int, err error) {
+	if t.fd < 0 {
+		return -1, -1, ErrUnknownTermSize
+	}
+	ws, err := unix.IoctlGetWinsize(t.fd, unix.TIOCGWINSZ)
+	if err != nil && t.sizeFromStdin {
+		ws, err = unix.IoctlGetWinsize(0, unix.TIOCGWINSZ)
+	}
+	if err != nil {
+		return -1, -1, ErrUnknownTermSize
+	}
+	return int(ws.Col), int(ws.Row), nil
+}
+
+// IsTerminal reports whether file descriptor of t refers to a terminal.
+func (t *Terminal) IsTerminal() bool {
+	return t.fd >= 0 && isTerminal(t.fd)
+}
+
+// Print formats using the default formats for its operands and writes to terminal.
+func (t *Terminal) Print(a ...interface{}) (n int, err error) {
+	return fmt.Fprint(t.out, a...)
+}
+
+// Printf formats according to a format specifier and writes to terminal.
+func (t *Terminal) Printf(format string, a ...interface{}) (n int, err error) {
+	return fmt.Fprintf(t.out, format, a...)
+}
+
+// Println formats using the default formats for its operands and writes to terminal.
+// Spaces are always added between operands and a newline is appended.
+func (t *Terminal) Println(a ...interface{}) (n int, err error) {
+	return fmt.Fprintln(t.out, a...)
+}
+
+// PrintAtPosition moves cursor to the specified position and prints.
+// It does not return the cursor to the initial position.
+func (t *Terminal) PrintAtPosition(column, row int, a ...interface{}) (n int, err error) {
+	t.MoveCursorTo(column, row)
+	return t.Print(a...)
+}
+
+// PrintAtPositionAndReturn moves cursor to the specified position, prints, and
+// then returns cursor to the inital position.
+func (t *Terminal) PrintAtPositionAndReturn(column, row int, a ...interface{}) (n int, err error) {
+	t.SaveCursorPosition()
+	t.MoveCursorTo(column, row)
+	defer t.RestoreCursorPosition()
+	return t.Print(a...)
+}
+
+// PrintCentered prints at specified row so that output is centered horizontally.
+// Width of output is calculated with VisibleWidth, so escape sequences and wide characters
+// are accounted for. If terminal size is unknown the method prints at current cursor position.
+func (t *Terminal) PrintCentered(row int, a ...interface{}) (n int, err error) {
+	out := fmt.Sprint(a...)
+	columns, _, err := t.Size()
+	if err != nil {
+		return t.Print(out)
+	}
+	column := (columns-VisibleWidth(out))/2 + 1
+	if column < 1 {
+		column = 1
+	}
+	return t.PrintAtPosition(column, row, out)
+}
+
+// ClearScreen clears screen
+func (t *Terminal) ClearScreen() {
+	t.Print(Clear)
+}
+
+// ClearScreenUp clears screen from current cursor position up
+func (t *Terminal) ClearScreenUp() {
+	t.Print(ClearUp)
+}
+
+// ClearScreenDown clears screen from current cursor position down
+func (t *Terminal) ClearScreenDown() {
+	t.Print(ClearDown)
+}
+
+// ClearLine deletes the whole line of text
+func (t *Terminal) ClearLine() {
+	t.Print(ClearL)
+}
+
+// ClearLineLeft deletes line left of cursor position
+func (t *Terminal) ClearLineLeft() {
+	t.Print(ClearLLeft)
+}
+
+// ClearLineRight deletes line right of cursor position
+func (t *Terminal) ClearLineRight() {
+	t.Print(ClearLRight)
+}
+
+// MoveCursorTo moves cursor to the specified position. Columns and rows are counted from 1,
+// so (1, 1) is upper left. This is the same convention as used by MouseEvent.
+// Will do nothing if x or y are out of bounds or we can not get size of terminal.
+func (t *Terminal) MoveCursorTo(column, row int) {
+	if t.fd >= 0 {
+		maxx, maxy, _ := t.Size()
+		if column > maxx || row > maxy {
+			return
+		}
+	}
+	t.Printf(CursorGotoTemplate, row, column)
+}
+
+// MoveCursorHome moves cursor to the upper left corner of the screen.
+func (t *Terminal) MoveCursorHome() {
+	t.Print(CursorHome)
+}
+
+// MoveCursorUp moves cursor up specified number of rows
+func (t *Terminal) MoveCursorUp(rows int) {
+	t.Printf(CursorMoveUpTemplate, rows)
+}
+
+// MoveCursorDown moves cursor down specified number of rows
+func (t *Terminal) MoveCursorDown(rows int) {
+	t.Printf(CursorMoveDownTemplate, rows)
+}
+
+// MoveCursorLeft moves cursor left specified number of columns
+func (t *Terminal) MoveCursorLeft(columns int) {
+	t.Printf(CursorMoveLeftTemplate, columns)
+}
+
+// MoveCursorRight moves cursor right specified number of columns
+func (t *Terminal) MoveCursorRight(columns int) {
+	t.Printf(CursorMoveRightTemplate, columns)
+}
+
+// MoveCursorToNextRow moves cursor to next row
+func (t *Terminal) MoveCursorToNextRow() {
+	t.Print(CursorMoveToNextRowTemplate)
+}
+
+// MoveCursorToRow places cursor at the beginning of specified row
+func (t *Terminal) MoveCursorToRow(row int) {
+	t.Printf(CursorMoveToRowTemplate, row)
+}
+
+// SaveCursorPosition saves current cursor position and attributes.
+func (t *Terminal) SaveCursorPosition() {
+	t.Print(CursorSave)
+}
+
+// RestoreCursorPosition returns cursor to position saved by SaveCursorPosition.
+func (t *Terminal) RestoreCursorPosition() {
+	t.Print(CursorRestore)
+}
@@ -0,0 +1,9 @@
+package termtools
+
+import "golang.org/x/sys/unix"
+
+// ioctl requests to get and set terminal attributes.
+const (
+	ioctlGetTermios = unix.TCGETS
+	ioctlSetTermios = unix.TCSETS
+)
@@ -0,0 +1,403 @@
+package termtools
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrInvalidTheme is returned when theme can not be parsed.
+	ErrInvalidTheme = errors.New("error: invalid theme")
+	// ErrUnknownField is returned when theme entry holds unknown field.
+	ErrUnknownField = errors.New("error: unknown field")
+	// ErrInvalidValue is returned when field of theme entry holds value of wrong type.
+	ErrInvalidValue = errors.New("error: invalid value")
+	// ErrDuplicateField is returned when theme entry sets the same field twice, for example
+	// with keys "fg" and "color".
+	ErrDuplicateField = errors.New("error: duplicate field")
+)
+
+// UnmarshalText implements encoding.TextUnmarshaler. Text must hold style specification
+// as accepted by ParseStyle. Name, Prefix, Suffix, Output and Extends fields of config are preserved.
+func (conf *PrinterConfig) UnmarshalText(text []byte) error {
+	parsed, err := ParseStyle(string(text))
+	if err != nil {
+		return &ConfigError{Entry: conf.Name, Field: "style", Value: string(text), Err: err}
+	}
+	parsed.Name, parsed.Prefix, parsed.Suffix, parsed.Output, parsed.Extends = conf.Name, conf.Prefix, conf.Suffix, conf.Output, conf.Extends
+	*conf = parsed
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts either a string holding style
+// specification (see ParseStyle) or an object with the following keys (case insensitive,
+// "-" and "_" are ignored): name, style (style specification applied before other keys),
+// color (fg, foreground), background (bg), underline_color (ul), underline_style, bold, faint,
+// italic, underline, blinking, reversed, hidden, strikethrough, overline, auto_foreground, prefix, suffix, extends.
+// Colors may be color names, hex values, color IDs or objects like {"r": 30, "g": 144, "b": 255}.
+// Object {"light": "blue", "dark": "#87d7ff"} defines AdaptiveColor.
+//
+// Keys are applied in fixed order: name, style, then other keys sorted alphabetically.
+// Keys setting the same field (like "bg" and "background" or "bold" and "Bold") are rejected
+// with ErrDuplicateField. Errors are returned as *ConfigError naming the failed field.
+func (conf *PrinterConfig) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		return conf.UnmarshalText([]byte(text))
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return &ConfigError{Entry: conf.Name, Field: "(entry)", Err: fmt.Errorf("%w: %v", ErrInvalidTheme, err)}
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	// name and style specification are applied first, so that errors mention the name
+	// and other fields may override the specification
+	sort.Slice(keys, func(i, j int) bool {
+		if ri, rj := keyRank(keys[i]), keyRank(keys[j]); ri != rj {
+			return ri < rj
+		}
+		return keys[i] < keys[j]
+	})
+	seen := make(map[string]string, len(keys))
+	for _, key := range keys {
+		field := fieldName(key)
+		if other, ok := seen[field]; ok {
+			return &ConfigError{Entry: conf.Name, Field: key, Value: fields[key],
+				Err: fmt.Errorf("%w: %q and %q", ErrDuplicateField, other, key)}
+		}
+		seen[field] = key
+		if err := conf.setField(key, fields[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func normalizeKey(key string) string {
+	return strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(strings.TrimSpace(key)))
+}
+
+// fieldAliases maps alternative keys to keys naming the same field of config.
+var fieldAliases = map[string]string{"fg": "color", "foreground": "color", "bg": "background", "ul": "underlinecolor"}
+
+// fieldName returns name of config field set by key.
+func fieldName(key string) string {
+	k := normalizeKey(key)
+	if field, ok := fieldAliases[k]; ok {
+		return field
+	}
+	return k
+}
+
+// keyRank tells order in which keys of JSON object are applied.
+func keyRank(key string) int {
+	switch normalizeKey(key) {
+	case "name":
+		return 0
+	case "style":
+		return 1
+	}
+	return 2
+}
+
+// setField sets field of config from decoded JSON value or from string of text theme.
+func (conf *PrinterConfig) setField(key string, value interface{}) error {
+	fail := func(err error) error {
+		return &ConfigError{Entry: conf.Name, Field: key, Value: value, Err: err}
+	}
+	flags := map[string]*bool{
+		"bold": &conf.Bold, "faint": &conf.Faint, "italic": &conf.Italic, "underline": &conf.Underline,
+		"blinking": &conf.Blinking, "reversed": &conf.Reversed, "hidden": &conf.Hidden,
+		"strikethrough": &conf.Strikethrough, "overline": &conf.Overline, "autoforeground": &conf.AutoForeground}
+	strs := map[string]*string{"name": &conf.Name, "prefix": &conf.Prefix, "suffix": &conf.Suffix, "extends": &conf.Extends}
+	colors := map[string]*interface{}{
+		"color": &conf.Color, "fg": &conf.Color, "foreground": &conf.Color,
+		"background": &conf.Background, "bg": &conf.Background,
+		"underlinecolor": &conf.UnderlineColor, "ul": &conf.UnderlineColor}
+	k := normalizeKey(key)
+	if flag, ok := flags[k]; ok {
+		b, err := boolValue(value)
+		if err != nil {
+			return fail(err)
+		}
+		*flag = b
+		return nil
+	}
+	if str, ok := strs[k]; ok {
+		s, ok := value.(string)
+		if !ok {
+			return fail(ErrInvalidValue)
+		}
+		*str = s
+		return nil
+	}
+	if field, ok := colors[k]; ok {
+		c, err := colorValueOf(value)
+		if err != nil {
+			return fail(err)
+		}
+		*field = c
+		return nil
+	}
+	switch k {
+	case "style":
+		s, ok := value.(string)
+		if !ok {
+			return fail(ErrInvalidValue)
+		}
+		if err := conf.UnmarshalText([]byte(s)); err != nil {
+			return fail(errors.Unwrap(err))
+		}
+		return nil
+	case "underlinestyle":
+		s, ok := value.(string)
+		if !ok {
+			return fail(ErrInvalidValue)
+		}
+		if err := conf.setSpecValue("underline", strings.ToLower(s)); err != nil {
+			return fail(err)
+		}
+		return nil
+	}
+	return fail(ErrUnknownField)
+}
+
+func boolValue(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		switch strings.ToLower(v) {
+		case "true", "yes", "on", "1":
+			return true, nil
+		case "false", "no", "off", "0", "":
+			return false, nil
+		}
+	}
+	return false, ErrInvalidValue
+}
+
+// colorValueOf converts decoded value to color identifier.
+func colorValueOf(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case float64:
+		if v == math.Trunc(v) {
+			if _, err := parseColor(int(v)); err == nil {
+				return int(v), nil
+			}
+		}
+	case string:
+		if c, ok := specColor(strings.TrimSpace(v)); ok {
+			return c, nil
+		}
+	case map[string]interface{}:
+		if _, ok := v["light"]; ok {
+			return adaptiveColorOf(v)
+		}
+		if _, ok := v["dark"]; ok {
+			return adaptiveColorOf(v)
+		}
+		var rgb [3]uint8
+		for i, key := range []string{"r", "g", "b"} {
+			component, ok := v[key].(float64)
+			if !ok || component < 0 || component > 255 || component != math.Trunc(component) {
+				return nil, ErrUnknownColor
+			}
+			rgb[i] = uint8(component)
+		}
+		return RGB{rgb[0], rgb[1], rgb[2]}, nil
+	}
+	return nil, ErrUnknownColor
+}
+
+func adaptiveColorOf(v map[string]interface{}) (interface{}, error) {
+	var ac AdaptiveColor
+	for key := range v {
+		if key != "light" && key != "dark" {
+			return nil, ErrUnknownColor
+		}
+	}
+	for _, field := range []struct {
+		key string
+		c   *interface{}
+	}{{"light", &ac.Light}, {"dark", &ac.Dark}} {
+		value, ok := v[field.key]
+		if !ok {
+			continue
+		}
+		c, err := colorValueOf(value)
+		if err != nil {
+			return nil, err
+		}
+		*field.c = c
+	}
+	if _, err := parseColor(ac); err != nil {
+		return nil, err
+	}
+	return ac, nil
+}
+
+// LoadTheme reads named printer configurations from r and adds them to PrintSuite
+// with Configure. Theme may be written in JSON or in a simple TOML/YAML-like format.
+//
+// JSON theme is an object where keys are printer names and values are either
+// style specifications (see ParseStyle) or objects as described in PrinterConfig UnmarshalJSON.
+// JSON array of objects with "name" key is also accepted.
+//
+//	{
+//	  "error": "bold red",
+//	  "warning": {"color": "#ffaf00", "prefix": "warning: "}
+//	}
+//
+// Text theme consists of lines. Lines starting with "#" or ";" are comments. Printer may be
+// defined on a single line as "name = spec" or "name: spec". Section starting with "[name]" or "name:"
+// defines printer field by field with "key = value" or "key: value" lines. In YAML-like
+// sections ("name:") fields must be indented. Values may be quoted.
+//
+//	error = "bold red"
+//	[warning]
+//	color = "#ffaf00"
+//	prefix = "warning: "
+//	info:
+//	  color: blue
+//	  italic: true
+//
+// Errors name the entry and field which failed.
+func (suite *PrintSuite) LoadTheme(r io.Reader) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	configs, err := parseTheme(data)
+	if err != nil {
+		return err
+	}
+	return suite.Configure(configs...)
+}
+
+// LoadThemeFile reads theme from file at path. See LoadTheme.
+func (suite *PrintSuite) LoadThemeFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return suite.LoadTheme(f)
+}
+
+func parseTheme(data []byte) ([]PrinterConfig, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || (trimmed[0] == '[' && json.Valid(trimmed))) {
+		return parseJSONTheme(trimmed)
+	}
+	return parseTextTheme(data)
+}
+
+func parseJSONTheme(data []byte) (configs []PrinterConfig, err error) {
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &configs); err != nil {
+			return nil, jsonThemeError(err)
+		}
+		return configs, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil { // opening brace
+		return nil, jsonThemeError(err)
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, jsonThemeError(err)
+		}
+		conf := PrinterConfig{Name: tok.(string)}
+		if err := dec.Decode(&conf); err != nil {
+			return nil, jsonThemeError(err)
+		}
+		configs = append(configs, conf)
+	}
+	return configs, nil
+}
+
+func jsonThemeError(err error) error {
+	var confErr *ConfigError
+	if errors.As(err, &confErr) {
+		return confErr
+	}
+	return fmt.Errorf("%w: %v", ErrInvalidTheme, err)
+}
+
+func parseTextTheme(data []byte) (configs []PrinterConfig, err error) {
+	var (
+		scanner = bufio.NewScanner(bytes.NewReader(data))
+		current = -1    // index of config being defined in section
+		yaml    = false // section started with "name:"
+		lineno  = 0
+	)
+	for scanner.Scan() {
+		lineno++
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		indented := raw[0] == ' ' || raw[0] == '\t'
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("%w: line %d: section header must end with \"]\"", ErrInvalidTheme, lineno)
+			}
+			configs = append(configs, PrinterConfig{Name: unquote(line[1 : len(line)-1])})
+			current, yaml = len(configs)-1, false
+			continue
+		}
+		key, value, ok := splitThemeLine(line)
+		if !ok {
+			return nil, fmt.Errorf("%w: line %d: expected \"key = value\" or \"key: value\"", ErrInvalidTheme, lineno)
+		}
+		if !indented && value == "" && strings.HasSuffix(line, ":") {
+			configs = append(configs, PrinterConfig{Name: unquote(key)})
+			current, yaml = len(configs)-1, true
+			continue
+		}
+		if current >= 0 && (!yaml || indented) {
+			if err := configs[current].setField(key, unquote(value)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		conf := PrinterConfig{Name: unquote(key)}
+		if err := conf.UnmarshalText([]byte(unquote(value))); err != nil {
+			return nil, err
+		}
+		configs, current = append(configs, conf), -1
+	}
+	return configs, scanner.Err()
+}
+
+// splitThemeLine splits line at the first "=" or ":".
+func splitThemeLine(line string) (key, value string, ok bool) {
+	i := strings.IndexAny(line, "=:")
+	if i <= 0 {
+		return "", "", false
+	}
+	return strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:]), true
+}
+
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
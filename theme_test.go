@@ -0,0 +1,250 @@
+package termtools
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_LoadTheme(t *testing.T) {
+	themes := map[string]string{
+		"json": `{
+			"error": "bold red",
+			"warning": {"color": "#ffaf00", "underline_style": "curly", "prefix": "warning: "},
+			"info": {"fg": 12, "bg": {"r": 0, "g": 0, "b": 95}, "style": "italic"}
+		}`,
+		"text": `# comment
+error = "bold red"
+[warning]
+color = "#ffaf00"
+underline-style = curly
+prefix = "warning: "
+info:
+  fg: 12
+  bg: "#00005f"
+  italic: yes
+`,
+	}
+	for format, theme := range themes {
+		var suite PrintSuite
+		if err := suite.LoadTheme(strings.NewReader(theme)); err != nil {
+			t.Errorf("%s: %v", format, err)
+			continue
+		}
+		cases := map[string]string{
+			"error":   Red + Bold + "x" + Reset,
+			"warning": "\x1b[38;2;255;175;0m" + CurlyUnderline + "warning: x" + Reset,
+			"info":    "\x1b[38;5;12m" + "\x1b[48;2;0;0;95m" + Italic + "x" + Reset,
+		}
+		for name, want := range cases {
+			p := suite.Use(name)
+			p.SetColorProfile(TrueColor)
+			if got := p.Sprint("x"); got != want {
+				t.Errorf("%s: printer %s: got %q want %q", format, name, got, want)
+			}
+		}
+	}
+
+	failing := map[string]string{
+		`{"warning": {"color": "purple"}}`:  "color",
+		`{"warning": {"bold": "maybe"}}`:    "bold",
+		`{"warning": {"shiny": true}}`:      "shiny",
+		`[{"name": "warning", "bg": 300}]`:  "bg",
+		"[warning]\ncolor = purple\n":       "color",
+		"warning:\n  underline_style: wavy": "underline_style",
+		"warning = bold purple":             "style",
+		// keys are applied in sorted order, so the first failing key is reported
+		`{"warning": {"shiny": true, "italic": 1, "color": "purple"}}`: "color",
+		`{"warning": {"fg": "red", "color": "blue"}}`:                  "fg",
+		`{"warning": {"bold": true, "Bold": false}}`:                   "bold",
+		`{"warning": {"underline-color": 1, "underline_color": 2}}`:    "underline_color",
+	}
+	for theme, field := range failing {
+		var suite PrintSuite
+		err := suite.LoadTheme(strings.NewReader(theme))
+		var confErr *ConfigError
+		if !errors.As(err, &confErr) || confErr.Entry != "warning" || confErr.Field != field {
+			t.Errorf("%q: expected error in field %q of entry warning, got %v", theme, field, err)
+		}
+	}
+	var suite PrintSuite
+	if err := suite.LoadTheme(strings.NewReader(`{"warning": {"ul": 1, "underline-color": 2}}`)); !errors.Is(err, ErrDuplicateField) {
+		t.Errorf("expected ErrDuplicateField, got %v", err)
+	}
+	if err := suite.LoadTheme(strings.NewReader("[warning\n")); !errors.Is(err, ErrInvalidTheme) {
+		t.Errorf("expected ErrInvalidTheme, got %v", err)
+	}
+	err := suite.Configure(PrinterConfig{Name: "a", Color: "purple"}, PrinterConfig{Name: "b", Background: -1})
+	var errs ConfigErrors
+	if !errors.As(err, &errs) || len(errs) != 2 || errs[0].Field != "Color" || errs[1].Field != "Background" {
+		t.Errorf("Configure: unexpected error %v", err)
+	}
+
+	// failing Configure leaves suite unchanged
+	suite = PrintSuite{}
+	suite.Configure(PrinterConfig{Name: "a", Bold: true})
+	if err := suite.Configure(PrinterConfig{Name: "a", Italic: true}, PrinterConfig{Name: "c"}, PrinterConfig{}); err != ErrFailedToProcessPrinterConfig {
+		t.Errorf("Configure with empty name: unexpected error %v", err)
+	}
+	err = suite.Configure(PrinterConfig{Name: "a", Italic: true}, PrinterConfig{Name: "b", Color: "purple"})
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Entry != "b" {
+		t.Errorf("Configure: unexpected error %v", err)
+	}
+	if _, ok := suite.configs["b"]; ok || suite.configs["a"].Italic || suite.SwitchTo("c") == nil {
+		t.Errorf("failed Configure changed suite: %v", suite.configs)
+	}
+	if got := suite.Use("a").style; got.Has(AttrItalic) || !got.Has(AttrBold) {
+		t.Errorf("failed Configure replaced printer: %+v", got)
+	}
+}
+
+func Test_ThemeInheritance(t *testing.T) {
+	var suite PrintSuite
+	suite.SetColorProfile(ANSI16)
+	sprint := func(name string) string {
+		p := *suite.Use(name)
+		p.SetColorProfile(ANSI16)
+		return p.Sprint("x")
+	}
+	err := suite.Configure(
+		PrinterConfig{Name: "fatal", Extends: "error", Background: "white"},
+		PrinterConfig{Name: "error", Extends: "warning", Color: "red"},
+		PrinterConfig{Name: "warning", Color: "yellow", Bold: true, Prefix: "! "},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := sprint("fatal"), Red+BWhite+Bold+"! x"+Reset; got != want {
+		t.Errorf("fatal: got %q want %q", got, want)
+	}
+	if err := suite.Configure(PrinterConfig{Name: "warning", Italic: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := sprint("fatal"), Red+BWhite+Italic+"x"+Reset; got != want {
+		t.Errorf("fatal after parent replaced: got %q want %q", got, want)
+	}
+
+	err = suite.Configure(PrinterConfig{Name: "orphan", Extends: "nobody"})
+	var errs ConfigErrors
+	if !errors.As(err, &errs) || !errors.Is(errs[0], ErrUnknownParent) || errs[0].Field != "Extends" {
+		t.Errorf("expected ErrUnknownParent, got %v", err)
+	}
+	suite.AddPrinter("nobody", Style{}.Underline().Printer())
+	if err := suite.Configure(PrinterConfig{Name: "orphan", Extends: "nobody", Color: "blue"}); err != nil {
+		t.Errorf("printer added with AddPrinter must be usable as parent: %v", err)
+	}
+	if got, want := sprint("orphan"), Blue+Underline+"x"+Reset; got != want {
+		t.Errorf("orphan: got %q want %q", got, want)
+	}
+
+	err = suite.Configure(PrinterConfig{Name: "a", Extends: "b"}, PrinterConfig{Name: "b", Extends: "a"})
+	if !errors.As(err, &errs) || !errors.Is(errs[0], ErrInheritanceCycle) {
+		t.Errorf("expected ErrInheritanceCycle, got %v", err)
+	}
+	// descendants failing after parent changed are reported in the same order every time
+	for _, name := range []string{"z", "y", "x"} {
+		if err := suite.Configure(PrinterConfig{Name: name, Extends: "warning"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 10; i++ {
+		err = suite.Configure(PrinterConfig{Name: "warning", Extends: "z"})
+		var entries []string
+		if errors.As(err, &errs) {
+			for _, e := range errs {
+				entries = append(entries, e.Entry)
+			}
+		}
+		if got := strings.Join(entries, " "); got != "warning error x y z fatal" {
+			t.Fatalf("Configure: got errors for %q", got)
+		}
+	}
+	if err := suite.LoadTheme(strings.NewReader("[debug]\nextends = warning\nfaint = true\n")); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := sprint("debug"), Faint+Italic+"x"+Reset; got != want {
+		t.Errorf("debug: got %q want %q", got, want)
+	}
+}
+
+func Test_UseTheme(t *testing.T) {
+	for _, theme := range Themes() {
+		var suite PrintSuite
+		if err := suite.UseTheme(theme); err != nil {
+			t.Errorf("%s: %v", theme, err)
+			continue
+		}
+		for _, name := range ThemePrinterNames {
+			p := *suite.Use(name)
+			for _, profile := range []ColorProfile{TrueColor, ANSI256, ANSI16} {
+				p.SetColorProfile(profile)
+				out := p.Sprint("x")
+				if profile < TrueColor && strings.Contains(out, "38;2;") {
+					t.Errorf("%s/%s: truecolor escape with %v profile: %q", theme, name, profile, out)
+				}
+				if profile == ANSI16 && strings.Contains(out, "38;5;") {
+					t.Errorf("%s/%s: 256 colors escape with %v profile: %q", theme, name, profile, out)
+				}
+			}
+			p.SetColorProfile(NoColor)
+			if out := p.Sprint("x"); out != "x" {
+				t.Errorf("%s/%s: NoColor output %q", theme, name, out)
+			}
+		}
+	}
+	var suite PrintSuite
+	suite.UseTheme("nord")
+	p := *suite.Use("error")
+	p.SetColorProfile(ANSI16)
+	if got, want := p.Sprint("x"), Red+Bold+"x"+Reset; got != want {
+		t.Errorf("nord error in ANSI16: got %q want %q", got, want)
+	}
+	p.SetColorProfile(ANSI256)
+	if got, want := p.Sprint("x"), "\x1b[38;5;131m"+Bold+"x"+Reset; got != want {
+		t.Errorf("nord error in ANSI256: got %q want %q", got, want)
+	}
+	if err := suite.UseTheme("nosuchtheme"); err != ErrUnknownTheme {
+		t.Errorf("expected ErrUnknownTheme, got %v", err)
+	}
+}
+
+func Test_Validate(t *testing.T) {
+	if c := SimulateCVD(RGB{255, 0, 0}, Deuteranopia); c != (RGB{163, 144, 0}) {
+		t.Errorf("SimulateCVD(red, Deuteranopia) = %v", c)
+	}
+	for _, v := range []Vision{NormalVision, Protanopia, Deuteranopia, Tritanopia} {
+		if c := SimulateCVD(RGB{128, 128, 128}, v); c != (RGB{128, 128, 128}) {
+			t.Errorf("SimulateCVD(gray, %v) = %v", v, c)
+		}
+	}
+	var suite PrintSuite
+	err := suite.Configure(
+		PrinterConfig{Name: "bad", Color: "#c81e1e"},
+		PrinterConfig{Name: "good", Color: "#1ea01e"},
+		PrinterConfig{Name: "dim", Color: "#202020"},
+		PrinterConfig{Name: "label", Background: "#ffff00", AutoForeground: true},
+		PrinterConfig{Name: "plain", Bold: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := suite.ValidateWith(ValidationOptions{Background: "black"})
+	want := []ValidationIssue{
+		{Printer: "bad", Vision: Protanopia},
+		{Printer: "dim", Vision: NormalVision},
+		{Printer: "bad", Other: "good", Vision: Deuteranopia},
+	}
+	if len(report.Issues) != len(want) {
+		t.Fatalf("got issues:\n%v", report)
+	}
+	for i, issue := range report.Issues {
+		if issue.Printer != want[i].Printer || issue.Other != want[i].Other || issue.Vision != want[i].Vision {
+			t.Errorf("issue %d: got %v", i, issue)
+		}
+	}
+	if report.OK() || report.Background != (RGB{}) {
+		t.Errorf("report: %+v", report)
+	}
+	if r := suite.ValidateWith(ValidationOptions{Background: "black", MinContrast: 1, MinDifference: 1}); !r.OK() || r.String() != "ok" {
+		t.Errorf("relaxed validation: %v", r)
+	}
+}
@@ -0,0 +1,129 @@
+package termtools
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrUnknownTheme is returned when requested built-in theme does not exist.
+var ErrUnknownTheme = errors.New("error: unknown theme")
+
+// ThemePrinterNames lists names of printers defined by every built-in theme.
+var ThemePrinterNames = []string{"fatal", "error", "warn", "info", "debug", "success", "muted", "highlight"}
+
+// themePalette holds colors of built-in theme. Truecolor values are accompanied
+// by base colors which are used with ANSI16 profile. Background color of the theme (bg)
+// is used as text color of "fatal" printer.
+type themePalette struct {
+	bg, red, yellow, blue, green, muted, accent CompleteColor
+}
+
+func themeColor(hex string, base string) CompleteColor {
+	return CompleteColor{TrueColor: hex, ANSI16: base}
+}
+
+func baseColor(name string) CompleteColor {
+	return CompleteColor{ANSI16: name}
+}
+
+// configs returns printer configurations of the theme.
+func (t themePalette) configs() []PrinterConfig {
+	return []PrinterConfig{
+		{Name: "fatal", Color: t.bg, Background: t.red, Bold: true},
+		{Name: "error", Color: t.red, Bold: true},
+		{Name: "warn", Color: t.yellow},
+		{Name: "info", Color: t.blue},
+		{Name: "debug", Color: t.muted},
+		{Name: "success", Color: t.green},
+		{Name: "muted", Color: t.muted, Faint: true},
+		{Name: "highlight", Color: t.accent, Bold: true},
+	}
+}
+
+var themes = map[string]func() []PrinterConfig{
+	"default": themePalette{
+		bg: baseColor("brightwhite"), red: baseColor("red"), yellow: baseColor("yellow"),
+		blue: baseColor("blue"), green: baseColor("green"), muted: baseColor("brightblack"), accent: baseColor("magenta"),
+	}.configs,
+	"solarized-dark": themePalette{
+		bg: themeColor("#002b36", "black"), red: themeColor("#dc322f", "red"), yellow: themeColor("#b58900", "yellow"),
+		blue: themeColor("#268bd2", "blue"), green: themeColor("#859900", "green"), muted: themeColor("#586e75", "brightblack"), accent: themeColor("#d33682", "magenta"),
+	}.configs,
+	"solarized-light": themePalette{
+		bg: themeColor("#fdf6e3", "brightwhite"), red: themeColor("#dc322f", "red"), yellow: themeColor("#b58900", "yellow"),
+		blue: themeColor("#268bd2", "blue"), green: themeColor("#859900", "green"), muted: themeColor("#93a1a1", "brightblack"), accent: themeColor("#6c71c4", "magenta"),
+	}.configs,
+	"dracula": themePalette{
+		bg: themeColor("#282a36", "black"), red: themeColor("#ff5555", "brightred"), yellow: themeColor("#f1fa8c", "brightyellow"),
+		blue: themeColor("#8be9fd", "brightcyan"), green: themeColor("#50fa7b", "brightgreen"), muted: themeColor("#6272a4", "brightblack"), accent: themeColor("#ff79c6", "brightmagenta"),
+	}.configs,
+	"nord": themePalette{
+		bg: themeColor("#2e3440", "black"), red: themeColor("#bf616a", "red"), yellow: themeColor("#ebcb8b", "yellow"),
+		blue: themeColor("#81a1c1", "blue"), green: themeColor("#a3be8c", "green"), muted: themeColor("#4c566a", "brightblack"), accent: themeColor("#88c0d0", "cyan"),
+	}.configs,
+	"gruvbox": themePalette{
+		bg: themeColor("#282828", "black"), red: themeColor("#fb4934", "brightred"), yellow: themeColor("#fabd2f", "brightyellow"),
+		blue: themeColor("#83a598", "brightblue"), green: themeColor("#b8bb26", "brightgreen"), muted: themeColor("#928374", "brightblack"), accent: themeColor("#fe8019", "yellow"),
+	}.configs,
+	"high-contrast": func() []PrinterConfig {
+		return []PrinterConfig{
+			{Name: "fatal", Color: "brightwhite", Background: "red", Bold: true},
+			{Name: "error", Color: "brightred", Bold: true},
+			{Name: "warn", Color: "brightyellow", Bold: true},
+			{Name: "info", Color: "brightcyan"},
+			{Name: "debug", Color: "white"},
+			{Name: "success", Color: "brightgreen", Bold: true},
+			{Name: "muted", Color: "white"},
+			{Name: "highlight", Color: "black", Background: "brightyellow", Bold: true},
+		}
+	},
+	// monochrome theme relies on attributes only
+	"monochrome": func() []PrinterConfig {
+		return []PrinterConfig{
+			{Name: "fatal", Bold: true, Reversed: true},
+			{Name: "error", Bold: true},
+			{Name: "warn", Bold: true, Italic: true},
+			{Name: "info"},
+			{Name: "debug", Faint: true},
+			{Name: "success", Underline: true},
+			{Name: "muted", Faint: true},
+			{Name: "highlight", Reversed: true},
+		}
+	},
+}
+
+// Themes returns sorted names of built-in themes.
+func Themes() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ThemeConfigs returns printer configurations of built-in theme. Configurations may be altered
+// and passed to PrintSuite Configure method.
+func ThemeConfigs(theme string) ([]PrinterConfig, error) {
+	configs, ok := themes[theme]
+	if !ok {
+		return nil, ErrUnknownTheme
+	}
+	return configs(), nil
+}
+
+// UseTheme configures PrintSuite with built-in theme. Available themes are: default, solarized-dark,
+// solarized-light, dracula, nord, gruvbox, high-contrast and monochrome (see also Themes).
+//
+// Each theme defines printers named "fatal", "error", "warn", "info", "debug", "success",
+// "muted" and "highlight" (see ThemePrinterNames). Printers with these names are replaced,
+// other printers are kept. Truecolor themes define carefully picked base colors which
+// are used with 16 color profile. Monochrome theme uses text attributes only.
+// With NoColor profile all printers output plain text.
+func (suite *PrintSuite) UseTheme(theme string) error {
+	configs, err := ThemeConfigs(theme)
+	if err != nil {
+		return err
+	}
+	return suite.Configure(configs...)
+}
@@ -0,0 +1,168 @@
+package termtools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultMinDifference is minimal CIELAB color difference between text colors of two printers
+// used by PrintSuite Validate method if ValidationOptions do not set it.
+const DefaultMinDifference = 10
+
+// ValidationOptions configure PrintSuite ValidateWith method.
+type ValidationOptions struct {
+	// Background is the assumed color of terminal background. It accepts the same values as Printer
+	// SetColor method. If nil or invalid, black or white is assumed depending on HasDarkBackground.
+	Background interface{}
+	// MinContrast is minimal contrast ratio between text and background of printer.
+	// If zero, DefaultMinContrast is used.
+	MinContrast float64
+	// MinDifference is minimal CIELAB difference between colors of two printers which are
+	// considered distinguishable. If zero, DefaultMinDifference is used.
+	MinDifference float64
+}
+
+// ValidationIssue describes a problem found by PrintSuite Validate method.
+type ValidationIssue struct {
+	// Printer holds name of printer. Other holds name of the second printer if issue concerns
+	// a pair of printers and is empty if text of Printer has low contrast with its background.
+	Printer, Other string
+	// Vision is the color vision the issue occurs with.
+	Vision Vision
+	// Contrast is contrast ratio of text and background of Printer if issue concerns low contrast.
+	Contrast float64
+	// Difference is difference of colors of two printers if issue concerns a pair of printers.
+	Difference float64
+}
+
+// String implements fmt.Stringer.
+func (issue ValidationIssue) String() string {
+	if issue.Other == "" {
+		return fmt.Sprintf("%s: low contrast %.2f with %s", issue.Printer, issue.Contrast, issue.Vision)
+	}
+	return fmt.Sprintf("%s and %s: indistinguishable with %s (difference %.1f)", issue.Printer, issue.Other, issue.Vision, issue.Difference)
+}
+
+// ValidationReport is returned by PrintSuite Validate method.
+type ValidationReport struct {
+	// Background is the background color assumed during validation.
+	Background RGB
+	Issues     []ValidationIssue
+}
+
+// OK reports whether no issues were found.
+func (r ValidationReport) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// String implements fmt.Stringer. It returns one line per issue or "ok" if there are no issues.
+func (r ValidationReport) String() string {
+	if r.OK() {
+		return "ok"
+	}
+	var lines []string
+	for _, issue := range r.Issues {
+		lines = append(lines, issue.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Validate checks printers of PrintSuite for legibility with default ValidationOptions. See ValidateWith.
+func (suite *PrintSuite) Validate() ValidationReport {
+	return suite.ValidateWith(ValidationOptions{})
+}
+
+// ValidateWith checks whether printers of PrintSuite are readable and can be told apart by people with
+// color vision deficiency. Colors of printers are checked with normal vision and as seen with protanopia,
+// deuteranopia and tritanopia (see SimulateCVD). The report lists
+//
+// printers with contrast ratio of text and background below opts.MinContrast (the lowest
+// contrast among kinds of vision is reported);
+//
+// pairs of printers which are distinguishable with normal vision but become indistinguishable
+// with color vision deficiency.
+//
+// Printers which do not set text color use default color of terminal and are not checked for contrast.
+func (suite *PrintSuite) ValidateWith(opts ValidationOptions) ValidationReport {
+	bg := ColorOptions{Background: opts.Background}.background()
+	minContrast, minDifference := opts.MinContrast, opts.MinDifference
+	if minContrast == 0 {
+		minContrast = DefaultMinContrast
+	}
+	if minDifference == 0 {
+		minDifference = DefaultMinDifference
+	}
+	report := ValidationReport{Background: bg}
+	var names []string
+	for name := range suite.available {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	visions := []Vision{NormalVision, Protanopia, Deuteranopia, Tritanopia}
+	type colors struct {
+		fg, bg   RGB
+		hasColor bool
+	}
+	seen := make([]colors, len(names))
+	for i, name := range names {
+		seen[i].fg, seen[i].bg, seen[i].hasColor = suite.available[name].style.colorsOn(bg)
+	}
+	for i, name := range names {
+		if !seen[i].hasColor {
+			continue
+		}
+		issue := ValidationIssue{Printer: name, Contrast: minContrast}
+		for _, v := range visions {
+			if c := ContrastRatio(SimulateCVD(seen[i].fg, v), SimulateCVD(seen[i].bg, v)); c < issue.Contrast {
+				issue.Vision, issue.Contrast = v, c
+			}
+		}
+		if issue.Contrast < minContrast {
+			report.Issues = append(report.Issues, issue)
+		}
+	}
+	difference := func(a, b colors, v Vision) float64 {
+		dfg := SimulateCVD(a.fg, v).lab().distance(SimulateCVD(b.fg, v).lab())
+		dbg := SimulateCVD(a.bg, v).lab().distance(SimulateCVD(b.bg, v).lab())
+		if dbg > dfg {
+			return dbg
+		}
+		return dfg
+	}
+	for i := range names {
+		for j := i + 1; j < len(names); j++ {
+			if difference(seen[i], seen[j], NormalVision) < minDifference {
+				continue
+			}
+			for _, v := range visions[1:] {
+				if d := difference(seen[i], seen[j], v); d < minDifference {
+					report.Issues = append(report.Issues, ValidationIssue{Printer: names[i], Other: names[j], Vision: v, Difference: d})
+					break
+				}
+			}
+		}
+	}
+	return report
+}
+
+// colorsOn returns text and background colors of style output on terminal with background bg.
+// Colors which are not set are replaced with bg and readable foreground for it. Flag hasColor reports
+// whether foreground was set explicitly or chosen automatically.
+func (s Style) colorsOn(bg RGB) (fg, background RGB, hasColor bool) {
+	dark := isDark(bg)
+	background = bg
+	if c, ok := s.bg.forBackground(dark).rgbValue(TrueColor); ok {
+		background = c
+	}
+	fg = ReadableForeground(bg)
+	if c, ok := s.fg.forBackground(dark).rgbValue(TrueColor); ok {
+		fg, hasColor = c, true
+	} else if s.autoForeground && s.bg.isSet() {
+		fg, hasColor = ReadableForeground(background), true
+	}
+	if s.Has(AttrReversed) {
+		fg, background = background, fg
+	}
+	return
+}